@@ -0,0 +1,112 @@
+package miniulid
+
+import (
+	"container/list"
+	"fmt"
+	"time"
+)
+
+// defaultBackfillMinutes is the number of minutes whose counters GenerateAt
+// remembers unless WithBackfillMinutes says otherwise.
+const defaultBackfillMinutes = 1024
+
+// backfillCounters holds one counter per historical minute, evicting the
+// least recently used minute beyond max.
+type backfillCounters struct {
+	max      int
+	order    *list.List // of *backfillEntry, most recently used first
+	byMinute map[time.Time]*list.Element
+}
+
+type backfillEntry struct {
+	minute  time.Time
+	counter *minuteCounter
+}
+
+// WithBackfillMinutes sets how many historical minutes GenerateAt keeps
+// counters for; the default is 1024. Each costs on the order of a hundred
+// bytes. It panics if n is below 1.
+func WithBackfillMinutes(n int) Option {
+	if n < 1 {
+		panic(fmt.Sprintf("miniulid: backfill minutes %d must be at least 1", n))
+	}
+	return func(g *Generator) {
+		g.backfill.max = n
+	}
+}
+
+// GenerateAt issues an ID for the minute of t, for example when importing
+// historical records. The minute must lie before the current one and before
+// the earliest minute Generate, GenerateMany, Resume, or RestoreState has
+// used since the generator was created or Reset, so that backfilled IDs never
+// repeat live ones. Each minute gets its own counter, so repeated calls for
+// the same minute yield distinct, ascending IDs; the counters follow the
+// generator's bit layout, reserved bits, and ceiling, but not WithRateLimit or
+// WithCounterStart. Counters are kept for the most recently used minutes only,
+// up to WithBackfillMinutes: once a minute is evicted, its counter restarts
+// at 0 and may reissue IDs, so process backfills roughly minute by minute.
+func (g *Generator) GenerateAt(t time.Time) (ID, error) {
+	return g.generateAt(g.now(), t)
+}
+
+func (g *Generator) generateAt(now, t time.Time) (ID, error) {
+	minute := g.wallMinute(t)
+	if current := g.clockMinute(now); !minute.Before(current) {
+		return 0, fmt.Errorf("miniulid: backfill minute %s is not before the current minute %s",
+			g.counter.inLocation(minute).Format(time.RFC3339), g.counter.inLocation(current).Format(time.RFC3339))
+	}
+	g.counter.mu.Lock()
+	first := g.counter.first
+	g.counter.mu.Unlock()
+	if !first.IsZero() && !minute.Before(first) {
+		return 0, fmt.Errorf("miniulid: backfill minute %s is not before the first live minute %s",
+			g.counter.inLocation(minute).Format(time.RFC3339), g.counter.inLocation(first).Format(time.RFC3339))
+	}
+
+	g.backfillMu.Lock()
+	defer g.backfillMu.Unlock()
+
+	mc := g.backfill.counter(minute, &g.counter)
+	_, counter, err := mc.nextLocked(minute)
+	if err != nil {
+		return 0, err
+	}
+	id, err := g.fromComponents(minute, counter)
+	if err != nil {
+		return 0, err
+	}
+	id = g.layout.pack(id, g.bits.counter)
+	if g.observer != nil {
+		g.observer.OnGenerate(id)
+	}
+	return id, nil
+}
+
+// counter returns the counter for minute, creating one configured like
+// template and evicting the least recently used minute if needed.
+func (b *backfillCounters) counter(minute time.Time, template *minuteCounter) *minuteCounter {
+	if b.byMinute == nil {
+		b.order = list.New()
+		b.byMinute = make(map[time.Time]*list.Element)
+	}
+	if e, ok := b.byMinute[minute]; ok {
+		b.order.MoveToFront(e)
+		return e.Value.(*backfillEntry).counter
+	}
+
+	if b.order.Len() >= b.max {
+		oldest := b.order.Back()
+		b.order.Remove(oldest)
+		delete(b.byMinute, oldest.Value.(*backfillEntry).minute)
+	}
+	mc := &minuteCounter{
+		width:    template.width,
+		reserved: template.reserved,
+		tag:      template.tag,
+		epoch:    template.epoch,
+		location: template.location,
+		ceiling:  template.ceiling,
+	}
+	b.byMinute[minute] = b.order.PushFront(&backfillEntry{minute: minute, counter: mc})
+	return mc
+}
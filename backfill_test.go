@@ -0,0 +1,121 @@
+package miniulid
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGenerateAtBackfill(t *testing.T) {
+	now := time.Date(2024, 8, 18, 15, 30, 0, 0, time.UTC)
+	past := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	g := NewGenerator()
+
+	ids := make([]ID, 100)
+	for i := range ids {
+		id, err := g.generateAt(now, past)
+		if err != nil {
+			t.Fatalf("generateAt %d error: %v", i, err)
+		}
+		if !id.MatchesTime(past) {
+			t.Fatalf("generateAt %d: %s is not in %v", i, id.Inspect(), past)
+		}
+		if i > 0 && id <= ids[i-1] {
+			t.Fatalf("generateAt %d: %v not after %v", i, id, ids[i-1])
+		}
+		ids[i] = id
+	}
+	if HasDuplicates(ids) {
+		t.Fatalf("backfilled IDs collide")
+	}
+
+	// Interleaving another minute keeps each minute's counter.
+	other, err := g.generateAt(now, past.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("generateAt error: %v", err)
+	}
+	if _, _, counter := other.Components(); counter != 0 {
+		t.Fatalf("new minute counter: got %d want 0", counter)
+	}
+	next, err := g.generateAt(now, past)
+	if err != nil {
+		t.Fatalf("generateAt error: %v", err)
+	}
+	if _, _, counter := next.Components(); counter != 100 {
+		t.Fatalf("resumed minute counter: got %d want 100", counter)
+	}
+
+	if _, err := g.generateAt(now, now.Add(30*time.Second)); err == nil {
+		t.Fatalf("expected error for the current minute")
+	}
+	if minute, _ := g.CurrentCounter(); !minute.IsZero() {
+		t.Fatalf("GenerateAt touched the live counter")
+	}
+}
+
+func TestGenerateAtLiveMinutes(t *testing.T) {
+	now := time.Date(2024, 8, 18, 15, 30, 0, 0, time.UTC)
+	g := NewGenerator()
+	live, err := g.generate(now)
+	if err != nil {
+		t.Fatalf("generate error: %v", err)
+	}
+	if _, err := g.generate(now.Add(time.Minute)); err != nil {
+		t.Fatalf("generate error: %v", err)
+	}
+
+	for _, m := range []time.Time{now, now.Add(time.Minute)} {
+		if id, err := g.generateAt(now.Add(2*time.Minute), m); err == nil {
+			t.Fatalf("generateAt(%v) reissued live minute: got %v, live %v", m, id, live)
+		}
+	}
+	if _, err := g.generateAt(now.Add(2*time.Minute), now.Add(-time.Minute)); err != nil {
+		t.Fatalf("generateAt before the live minutes error: %v", err)
+	}
+
+	g.Reset()
+	if _, err := g.generateAt(now.Add(2*time.Minute), now); err != nil {
+		t.Fatalf("generateAt after Reset error: %v", err)
+	}
+}
+
+func TestGenerateAtEviction(t *testing.T) {
+	now := time.Date(2024, 8, 18, 15, 30, 0, 0, time.UTC)
+	g := NewGenerator(WithBackfillMinutes(2))
+	minutes := []time.Time{
+		now.Add(-3 * time.Minute),
+		now.Add(-2 * time.Minute),
+		now.Add(-3 * time.Minute),
+		now.Add(-1 * time.Minute),
+	}
+	for _, m := range minutes {
+		if _, err := g.generateAt(now, m); err != nil {
+			t.Fatalf("generateAt error: %v", err)
+		}
+	}
+	if n := len(g.backfill.byMinute); n != 2 {
+		t.Fatalf("backfill cache holds %d minutes, want 2", n)
+	}
+
+	// -3 was used more recently than -2, so -2 was evicted and restarts.
+	id, err := g.generateAt(now, now.Add(-3*time.Minute))
+	if err != nil {
+		t.Fatalf("generateAt error: %v", err)
+	}
+	if _, _, counter := id.Components(); counter != 2 {
+		t.Fatalf("retained minute counter: got %d want 2", counter)
+	}
+	id, err = g.generateAt(now, now.Add(-2*time.Minute))
+	if err != nil {
+		t.Fatalf("generateAt error: %v", err)
+	}
+	if _, _, counter := id.Components(); counter != 0 {
+		t.Fatalf("evicted minute counter: got %d want 0", counter)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected panic for zero backfill minutes")
+		}
+	}()
+	WithBackfillMinutes(0)
+}
@@ -0,0 +1,113 @@
+package miniulid
+
+import (
+	"encoding/base64"
+	"fmt"
+	"slices"
+)
+
+// binarySize is the length of the compact big-endian form.
+const binarySize = 5
+
+// base64Size is the length of the base64url form: 40 bits need 7 characters,
+// the last of which carries two zero padding bits.
+const base64Size = 7
+
+// base64Encoding is unpadded base64url that rejects non-zero padding bits, so
+// every ID has exactly one accepted form.
+var base64Encoding = base64.RawURLEncoding.Strict()
+
+// AppendBinary appends the 5-byte big-endian form of id to b. It implements
+// encoding.BinaryAppender.
+func (id ID) AppendBinary(b []byte) ([]byte, error) {
+	b = slices.Grow(b, binarySize)
+	n := len(b)
+	b = b[:n+binarySize]
+	id.putBytes(b[n:])
+	return b, nil
+}
+
+// MarshalBinary returns the 5-byte big-endian form of id. Comparing two
+// encodings with bytes.Compare orders them like the IDs. It implements
+// encoding.BinaryMarshaler.
+func (id ID) MarshalBinary() ([]byte, error) {
+	return id.AppendBinary(make([]byte, 0, binarySize))
+}
+
+// UnmarshalBinary decodes the 5-byte form produced by MarshalBinary,
+// rejecting other lengths and, like Parse, minute-of-day fields of 1440 or
+// more. It implements encoding.BinaryUnmarshaler.
+func (id *ID) UnmarshalBinary(data []byte) error {
+	if len(data) != binarySize {
+		return fmt.Errorf("miniulid: binary form must be %d bytes, got %d", binarySize, len(data))
+	}
+	parsed, err := checkMinute(idFromBytes(data))
+	if err != nil {
+		return err
+	}
+	*id = parsed
+	return nil
+}
+
+// Base64String returns the 7-character base64url form of id's 5-byte
+// big-endian value, without '=' padding, for HTTP headers and JWT claims. Unlike
+// String it does not sort in ID order.
+func (id ID) Base64String() string {
+	var b [binarySize]byte
+	id.putBytes(b[:])
+	return base64Encoding.EncodeToString(b[:])
+}
+
+// ParseBase64 decodes the form produced by Base64String, rejecting other
+// lengths, padded input, and non-zero padding bits. Like Parse it rejects
+// minute-of-day fields of 1440 or more with ErrInvalidMinute.
+func ParseBase64(s string) (ID, error) {
+	if len(s) != base64Size {
+		return 0, fmt.Errorf("miniulid: base64 form must be %d characters", base64Size)
+	}
+	var b [binarySize]byte
+	if _, err := base64Encoding.Decode(b[:], []byte(s)); err != nil {
+		return 0, fmt.Errorf("miniulid: invalid base64 form %q: %w", s, err)
+	}
+	return checkMinute(idFromBytes(b[:]))
+}
+
+// EncodeSlice packs ids into 5 bytes each, big-endian, in order. Bits above
+// the 40-bit range are dropped.
+func EncodeSlice(ids []ID) []byte {
+	buf := make([]byte, binarySize*len(ids))
+	for i, id := range ids {
+		id.putBytes(buf[i*binarySize:])
+	}
+	return buf
+}
+
+// DecodeSlice unpacks IDs produced by EncodeSlice.
+func DecodeSlice(b []byte) ([]ID, error) {
+	if len(b)%binarySize != 0 {
+		return nil, fmt.Errorf("miniulid: packed length %d is not a multiple of %d", len(b), binarySize)
+	}
+	ids := make([]ID, len(b)/binarySize)
+	for i := range ids {
+		ids[i] = idFromBytes(b[i*binarySize:])
+	}
+	return ids, nil
+}
+
+// putBytes writes the 40-bit value big-endian into b, which must hold 5 bytes.
+func (id ID) putBytes(b []byte) {
+	value := uint64(id)
+	for i := binarySize - 1; i >= 0; i-- {
+		b[i] = byte(value)
+		value >>= 8
+	}
+}
+
+// idFromBytes reads a big-endian 40-bit value from b, which must hold 5 bytes.
+func idFromBytes(b []byte) ID {
+	var value uint64
+	for _, c := range b[:binarySize] {
+		value = value<<8 | uint64(c)
+	}
+	return ID(value)
+}
@@ -0,0 +1,176 @@
+package miniulid
+
+import (
+	"bytes"
+	"encoding"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestEncodeSlice(t *testing.T) {
+	ids, err := NewGenerator().generateMany(time.Date(2024, 8, 18, 15, 30, 0, 0, time.UTC), 50000)
+	if err != nil {
+		t.Fatalf("generateMany error: %v", err)
+	}
+	ids = append(ids, 0, maxValue)
+
+	packed := EncodeSlice(ids)
+	if len(packed) != binarySize*len(ids) {
+		t.Fatalf("EncodeSlice length: got %d want %d", len(packed), binarySize*len(ids))
+	}
+	back, err := DecodeSlice(packed)
+	if err != nil {
+		t.Fatalf("DecodeSlice error: %v", err)
+	}
+	if !slices.Equal(back, ids) {
+		t.Fatalf("DecodeSlice round trip mismatch")
+	}
+
+	if _, err := DecodeSlice(packed[:len(packed)-1]); err == nil {
+		t.Fatalf("expected error for truncated input")
+	}
+	if ids, err := DecodeSlice(nil); err != nil || len(ids) != 0 {
+		t.Fatalf("DecodeSlice(nil): got %v, %v", ids, err)
+	}
+}
+
+func BenchmarkEncodeSlice(b *testing.B) {
+	ids := make([]ID, 1024)
+	for i := range ids {
+		ids[i] = ID(56755782866 + i)
+	}
+	b.SetBytes(int64(binarySize * len(ids)))
+	for b.Loop() {
+		_ = EncodeSlice(ids)
+	}
+}
+
+func BenchmarkDecodeSlice(b *testing.B) {
+	ids := make([]ID, 1024)
+	for i := range ids {
+		ids[i] = ID(56755782866 + i)
+	}
+	packed := EncodeSlice(ids)
+	b.SetBytes(int64(len(packed)))
+	for b.Loop() {
+		_, _ = DecodeSlice(packed)
+	}
+}
+
+func TestAppenders(t *testing.T) {
+	var (
+		_ encoding.BinaryAppender = ID(0)
+		_ encoding.TextAppender   = ID(0)
+	)
+
+	id := ID(56755782866)
+	b, _ := id.AppendText([]byte("id="))
+	if got, want := string(b), "id="+id.String(); got != want {
+		t.Fatalf("AppendText: got %q want %q", got, want)
+	}
+	b, _ = id.AppendBinary([]byte{0xff})
+	if got, want := b, []byte{0xff, 0x0d, 0x36, 0xe8, 0x84, 0xd2}; !slices.Equal(got, want) {
+		t.Fatalf("AppendBinary: got % x want % x", got, want)
+	}
+
+	buf := make([]byte, 0, 64)
+	allocs := testing.AllocsPerRun(100, func() {
+		buf, _ = id.AppendText(buf[:0])
+		buf, _ = id.AppendBinary(buf)
+	})
+	if allocs != 0 {
+		t.Fatalf("appenders allocated %v times", allocs)
+	}
+}
+
+func BenchmarkAppendText(b *testing.B) {
+	id := ID(56755782866)
+	buf := make([]byte, 0, EncodedLen)
+	b.ReportAllocs()
+	for b.Loop() {
+		buf, _ = id.AppendText(buf[:0])
+	}
+}
+
+func BenchmarkAppendBinary(b *testing.B) {
+	id := ID(56755782866)
+	buf := make([]byte, 0, binarySize)
+	b.ReportAllocs()
+	for b.Loop() {
+		buf, _ = id.AppendBinary(buf[:0])
+	}
+}
+
+func TestBase64(t *testing.T) {
+	ids, err := NewGenerator().generateMany(time.Date(2024, 8, 18, 15, 30, 0, 0, time.UTC), 1000)
+	if err != nil {
+		t.Fatalf("generateMany error: %v", err)
+	}
+	for _, id := range append(ids, 0, ID(56755782866)) {
+		s := id.Base64String()
+		if len(s) != 7 {
+			t.Fatalf("Base64String(%v): got %q, want 7 characters", id, s)
+		}
+		back, err := ParseBase64(s)
+		if err != nil || back != id {
+			t.Fatalf("ParseBase64(%q): got %v, %v want %v", s, back, err, id)
+		}
+	}
+	if got := ID(56755782866).Base64String(); got != "DTbohNI" {
+		t.Fatalf("Base64String: got %q want %q", got, "DTbohNI")
+	}
+
+	for _, input := range []string{"DTbohN", "DTbohNI=", "DTbohNJ", "DTbo+NI"} {
+		if _, err := ParseBase64(input); err == nil {
+			t.Fatalf("ParseBase64(%q): expected error", input)
+		}
+	}
+	if _, err := ParseBase64(ID(maxValue).Base64String()); !errors.Is(err, ErrInvalidMinute) {
+		t.Fatalf("expected ErrInvalidMinute, got %v", err)
+	}
+}
+
+func TestBinaryMarshaler(t *testing.T) {
+	var (
+		_ encoding.BinaryMarshaler   = ID(0)
+		_ encoding.BinaryUnmarshaler = (*ID)(nil)
+	)
+
+	id := ID(56755782866)
+	data, err := id.MarshalBinary()
+	if err != nil || !slices.Equal(data, []byte{0x0d, 0x36, 0xe8, 0x84, 0xd2}) {
+		t.Fatalf("MarshalBinary: got % x, %v", data, err)
+	}
+	var back ID
+	if err := back.UnmarshalBinary(data); err != nil || back != id {
+		t.Fatalf("UnmarshalBinary: got %v, %v", back, err)
+	}
+
+	ids, err := GenerateSequence(time.Date(2024, 8, 18, 15, 30, 0, 0, time.UTC), 1<<counterBits+300)
+	if err != nil {
+		t.Fatalf("GenerateSequence error: %v", err)
+	}
+	ids = append(ids, 0, 1, 255, 256)
+	encoded := make([][]byte, len(ids))
+	for i, id := range ids {
+		encoded[i], _ = id.MarshalBinary()
+	}
+	slices.SortFunc(encoded, bytes.Compare)
+	slices.Sort(ids)
+	for i, data := range encoded {
+		if err := back.UnmarshalBinary(data); err != nil || back != ids[i] {
+			t.Fatalf("byte order differs from ID order at %d: %v vs %v", i, back, ids[i])
+		}
+	}
+
+	for _, bad := range [][]byte{nil, data[:4], append(data, 0)} {
+		if err := back.UnmarshalBinary(bad); err == nil {
+			t.Fatalf("UnmarshalBinary(% x): expected error", bad)
+		}
+	}
+	if err := back.UnmarshalBinary([]byte{0xff, 0xff, 0xff, 0xff, 0xff}); !errors.Is(err, ErrInvalidMinute) {
+		t.Fatalf("expected ErrInvalidMinute, got %v", err)
+	}
+}
@@ -0,0 +1,91 @@
+package miniulid
+
+import (
+	"fmt"
+	"time"
+)
+
+// BitLayout sets how many of an ID's 40 bits hold the day, minute-of-day, and
+// counter fields. Widening the counter raises the number of IDs per minute at
+// the cost of a shorter date range. IDs built with different bit layouts are
+// incompatible: they neither sort together nor decode with each other's
+// layout, and the ID methods such as ID.Time and Parse's minute check assume
+// the default 15/11/14 split.
+type BitLayout struct {
+	days    uint8
+	minutes uint8
+	counter uint8
+}
+
+var defaultBitLayout = BitLayout{days: daysBits, minutes: minutesBits, counter: counterBits}
+
+// NewBitLayout returns the layout with the given field widths. They must sum
+// to 40, the minute field needs at least 11 bits to hold 1439, the counter may
+// take at most 16 bits, and the day field may not exceed the default 15 bits
+// since the date range ends in 2109 regardless.
+func NewBitLayout(daysBits, minutesBits, counterBits uint8) (BitLayout, error) {
+	b := BitLayout{days: daysBits, minutes: minutesBits, counter: counterBits}
+	if err := b.validate(); err != nil {
+		return BitLayout{}, err
+	}
+	return b, nil
+}
+
+func (b BitLayout) validate() error {
+	switch {
+	case int(b.days)+int(b.minutes)+int(b.counter) != Bits:
+		return fmt.Errorf("miniulid: bit layout %d/%d/%d does not sum to %d", b.days, b.minutes, b.counter, Bits)
+	case b.days < 1 || b.days > daysBits:
+		return fmt.Errorf("miniulid: day field of %d bits must be between 1 and %d", b.days, daysBits)
+	case b.minutes < minutesBits:
+		return fmt.Errorf("miniulid: minute field of %d bits must be at least %d", b.minutes, minutesBits)
+	case b.counter < 1 || b.counter > 16:
+		return fmt.Errorf("miniulid: counter field of %d bits must be between 1 and 16", b.counter)
+	}
+	return nil
+}
+
+// CounterMask returns the highest counter value the layout can hold.
+func (b BitLayout) CounterMask() uint16 {
+	return uint16(1<<b.counter - 1)
+}
+
+// FromComponents packs raw day, minute-of-day, and counter fields into an ID
+// using layout b.
+func (b BitLayout) FromComponents(days, minuteOfDay, counter uint16) (ID, error) {
+	if uint64(days) >= 1<<b.days {
+		return 0, fmt.Errorf("miniulid: day value overflow (max %d)", 1<<b.days-1)
+	}
+	if minuteOfDay >= minutesPerDay {
+		return 0, fmt.Errorf("miniulid: minute of day out of range (max %d)", minutesPerDay-1)
+	}
+	if counter > b.CounterMask() {
+		return 0, fmt.Errorf("miniulid: counter value overflow (max %d)", b.CounterMask())
+	}
+	return ID(uint64(days)<<(b.minutes+b.counter) |
+		uint64(minuteOfDay)<<b.counter |
+		uint64(counter)), nil
+}
+
+// Components returns the day, minute, and counter fields of an ID built with
+// layout b.
+func (b BitLayout) Components(id ID) (days uint16, minuteOfDay uint16, counter uint16) {
+	value := uint64(id)
+	counter = uint16(value & (1<<b.counter - 1))
+	value >>= b.counter
+	minuteOfDay = uint16(value & (1<<b.minutes - 1))
+	value >>= b.minutes
+	days = uint16(value & (1<<b.days - 1))
+	return
+}
+
+// Time returns the minute-precision UTC time of an ID built with layout b.
+func (b BitLayout) Time(id ID) time.Time {
+	days, minuteOfDay, _ := b.Components(id)
+	return time.Date(2020, time.January, 1+int(days), 0, int(minuteOfDay), 0, 0, time.UTC)
+}
+
+// String returns the field widths as days/minutes/counter.
+func (b BitLayout) String() string {
+	return fmt.Sprintf("%d/%d/%d", b.days, b.minutes, b.counter)
+}
@@ -0,0 +1,80 @@
+package miniulid
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWithBitLayoutWiderCounter(t *testing.T) {
+	now := time.Date(2024, 8, 18, 15, 30, 0, 0, time.UTC)
+	g := NewGenerator(WithBitLayout(13, 11, 16))
+
+	ids, err := g.generateMany(now, 1<<counterBits+10)
+	if err != nil {
+		t.Fatalf("generateMany error: %v", err)
+	}
+	for i := 1; i < len(ids); i++ {
+		if ids[i] <= ids[i-1] {
+			t.Fatalf("IDs not ascending at %d: %v then %v", i, ids[i-1], ids[i])
+		}
+	}
+
+	last := ids[len(ids)-1]
+	if got := g.Time(last); !got.Equal(now) {
+		t.Fatalf("Time: got %v want %v, a 16-bit counter should not roll over", got, now)
+	}
+	days, minuteOfDay, counter := g.bits.Components(last)
+	if days != 1691 || minuteOfDay != 930 || counter != 1<<counterBits+9 {
+		t.Fatalf("Components: got %d/%d/%d", days, minuteOfDay, counter)
+	}
+	if id, _ := g.bits.FromComponents(days, minuteOfDay, counter); id != last {
+		t.Fatalf("FromComponents: got %v want %v", id, last)
+	}
+
+	entropyFirst := NewGenerator(WithBitLayout(13, 11, 16), WithLayout(LayoutEntropyFirst))
+	id, err := entropyFirst.generate(now)
+	if err != nil {
+		t.Fatalf("entropy-first generate error: %v", err)
+	}
+	if got := entropyFirst.Time(id); !got.Equal(now) {
+		t.Fatalf("entropy-first Time: got %v want %v", got, now)
+	}
+
+	if err := g.resume(now, last); err != nil {
+		t.Fatalf("resume error: %v", err)
+	}
+	if st := g.State(); st.Counter != counter {
+		t.Fatalf("State after resume: got %d want %d", st.Counter, counter)
+	}
+
+	short := NewGenerator(WithBitLayout(13, 11, 16))
+	if _, err := short.generate(time.Date(2043, 1, 1, 0, 0, 0, 0, time.UTC)); err == nil {
+		t.Fatalf("expected error past the 13-bit day range")
+	}
+}
+
+func TestNewBitLayoutInvalid(t *testing.T) {
+	for _, w := range [][3]uint8{
+		{15, 11, 13},
+		{12, 11, 17},
+		{16, 11, 13},
+		{19, 10, 11},
+		{0, 24, 16},
+	} {
+		if _, err := NewBitLayout(w[0], w[1], w[2]); err == nil {
+			t.Fatalf("NewBitLayout(%v): expected error", w)
+		}
+	}
+
+	b, err := NewBitLayout(daysBits, minutesBits, counterBits)
+	if err != nil || b != defaultBitLayout {
+		t.Fatalf("default widths: got %v, %v", b, err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected WithBitLayout to panic on invalid widths")
+		}
+	}()
+	WithBitLayout(15, 11, 15)
+}
@@ -0,0 +1,56 @@
+package miniulid
+
+import "fmt"
+
+const (
+	cborByteString = 0x40 | binarySize
+	cborMajorMask  = 0xe0
+	cborMajorTag   = 0xc0
+)
+
+var errCBOR = fmt.Errorf("miniulid: CBOR value must be a %d-byte byte string", binarySize)
+
+// MarshalCBOR encodes the ID as a 5-byte CBOR byte string. It satisfies the
+// fxamacker/cbor Marshaler interface; callers that want a semantic tag can
+// register ID in their own TagSet.
+func (id ID) MarshalCBOR() ([]byte, error) {
+	buf := make([]byte, 1+binarySize)
+	buf[0] = cborByteString
+	id.putBytes(buf[1:])
+	return buf, nil
+}
+
+// UnmarshalCBOR decodes a 5-byte CBOR byte string, optionally wrapped in a
+// semantic tag.
+func (id *ID) UnmarshalCBOR(data []byte) error {
+	data, err := skipCBORTag(data)
+	if err != nil {
+		return err
+	}
+	if len(data) != 1+binarySize || data[0] != cborByteString {
+		return errCBOR
+	}
+	*id = idFromBytes(data[1:])
+	return nil
+}
+
+// skipCBORTag strips a leading tag header, if any.
+func skipCBORTag(data []byte) ([]byte, error) {
+	if len(data) == 0 || data[0]&cborMajorMask != cborMajorTag {
+		return data, nil
+	}
+
+	var n int
+	switch info := data[0] & 0x1f; {
+	case info < 24:
+		n = 1
+	case info <= 27:
+		n = 1 + 1<<(info-24)
+	default:
+		return nil, errCBOR
+	}
+	if len(data) < n {
+		return nil, errCBOR
+	}
+	return data[n:], nil
+}
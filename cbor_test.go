@@ -0,0 +1,45 @@
+package miniulid
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCBORRoundTrip(t *testing.T) {
+	id, err := GenerateWithComponents(time.Date(2024, 8, 18, 15, 30, 0, 0, time.UTC), 1234)
+	if err != nil {
+		t.Fatalf("GenerateWithComponents error: %v", err)
+	}
+
+	data, err := id.MarshalCBOR()
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	if len(data) != 1+binarySize || data[0] != 0x45 {
+		t.Fatalf("unexpected CBOR header: % x", data)
+	}
+
+	var back ID
+	if err := back.UnmarshalCBOR(data); err != nil {
+		t.Fatalf("UnmarshalCBOR error: %v", err)
+	}
+	if back != id {
+		t.Fatalf("CBOR mismatch: got %v want %v", back, id)
+	}
+
+	// Tag 40000 (0xd9 0x9c 0x40) wrapping the same byte string.
+	tagged := append([]byte{0xd9, 0x9c, 0x40}, data...)
+	back = 0
+	if err := back.UnmarshalCBOR(tagged); err != nil {
+		t.Fatalf("UnmarshalCBOR tagged error: %v", err)
+	}
+	if back != id {
+		t.Fatalf("tagged CBOR mismatch: got %v want %v", back, id)
+	}
+
+	if err := back.UnmarshalCBOR(bytes.Repeat([]byte{0x44}, 5)); !errors.Is(err, errCBOR) {
+		t.Fatalf("expected errCBOR, got %v", err)
+	}
+}
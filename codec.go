@@ -0,0 +1,68 @@
+package miniulid
+
+import "fmt"
+
+var errAlphabet = fmt.Errorf("miniulid: alphabet must be %d unique ASCII characters", len(Alphabet))
+
+// Codec encodes IDs with a custom 32-character alphabet, for example a
+// permutation of Alphabet that makes public IDs harder to recognize. Decoding
+// is exact: no case folding or Crockford substitutions are applied.
+type Codec struct {
+	encode [32]byte
+	decode [256]byte
+}
+
+const codecInvalid = 0xff
+
+// NewCodec returns a Codec for alphabet, which must contain 32 unique ASCII characters.
+func NewCodec(alphabet string) (*Codec, error) {
+	if len(alphabet) != len(Alphabet) {
+		return nil, errAlphabet
+	}
+
+	c := &Codec{}
+	for i := range c.decode {
+		c.decode[i] = codecInvalid
+	}
+	for i := 0; i < len(alphabet); i++ {
+		ch := alphabet[i]
+		if ch >= 0x80 || c.decode[ch] != codecInvalid {
+			return nil, errAlphabet
+		}
+		c.encode[i] = ch
+		c.decode[ch] = byte(i)
+	}
+	return c, nil
+}
+
+// Encode returns the 8-character form of id in the codec's alphabet.
+func (c *Codec) Encode(id ID) string {
+	var buf [totalSize]byte
+	value := uint64(id)
+
+	for i := totalSize - 1; i >= 0; i-- {
+		buf[i] = c.encode[value&31]
+		value >>= 5
+	}
+
+	return string(buf[:])
+}
+
+// Decode parses a string produced by Encode. Like Parse, it rejects values
+// whose minute-of-day field is out of range.
+func (c *Codec) Decode(encoded string) (ID, error) {
+	if len(encoded) != totalSize {
+		return 0, errLength
+	}
+
+	var value uint64
+	for i := 0; i < len(encoded); i++ {
+		v := c.decode[encoded[i]]
+		if v == codecInvalid {
+			return 0, fmt.Errorf("%w: %q", errInvalidChar, encoded[i])
+		}
+		value = (value << 5) | uint64(v)
+	}
+
+	return checkMinute(ID(value))
+}
@@ -0,0 +1,45 @@
+package miniulid
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCodec(t *testing.T) {
+	const shuffled = "QWERTYUPASDFGHJKZXCVBNM23456789L"
+	c, err := NewCodec(shuffled)
+	if err != nil {
+		t.Fatalf("NewCodec error: %v", err)
+	}
+
+	for _, id := range []ID{0, 7, 56755782866} {
+		encoded := c.Encode(id)
+		if len(encoded) != EncodedLen {
+			t.Fatalf("Encode length: got %d want %d", len(encoded), EncodedLen)
+		}
+		back, err := c.Decode(encoded)
+		if err != nil {
+			t.Fatalf("Decode(%q) error: %v", encoded, err)
+		}
+		if back != id {
+			t.Fatalf("Decode(%q): got %v want %v", encoded, back, id)
+		}
+	}
+	if got := c.Encode(7); got != "QQQQQQQP" {
+		t.Fatalf("Encode(7): got %q want %q", got, "QQQQQQQP")
+	}
+	if _, err := c.Decode("QQQQQQQ0"); !errors.Is(err, errInvalidChar) {
+		t.Fatalf("expected errInvalidChar, got %v", err)
+	}
+
+	for _, alphabet := range []string{
+		"",
+		shuffled[:31],
+		shuffled[:31] + "Q",
+		shuffled[:31] + "é",
+	} {
+		if _, err := NewCodec(alphabet); !errors.Is(err, errAlphabet) {
+			t.Fatalf("NewCodec(%q): expected errAlphabet, got %v", alphabet, err)
+		}
+	}
+}
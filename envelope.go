@@ -0,0 +1,101 @@
+package miniulid
+
+import (
+	"fmt"
+	"time"
+)
+
+// envelopeSize is the length of the enveloped form: a tag character followed
+// by the encoded ID.
+const envelopeSize = 1 + totalSize
+
+// Meta describes how the ID inside an envelope was built.
+type Meta struct {
+	// Version identifies the epoch and field widths. Version 0, the only one
+	// defined, is the 2020-01-01 UTC epoch with the default 15/11/14 widths.
+	Version uint8
+	// Layout is the field order of the ID.
+	Layout Layout
+}
+
+// tag packs m into one Crockford character: four version bits above one
+// layout bit.
+func (m Meta) tag() (byte, error) {
+	if m.Version > 15 || m.Layout > LayoutEntropyFirst {
+		return 0, fmt.Errorf("miniulid: cannot encode envelope version %d with layout %v", m.Version, m.Layout)
+	}
+	return encodeAlphabet[m.Version<<1|uint8(m.Layout)], nil
+}
+
+// Envelope returns the self-describing form of id: a tag character encoding m
+// followed by the 8-character Crockford form, 9 characters in all. It fails
+// if m has a version above 15 or an unknown layout.
+func (m Meta) Envelope(id ID) (string, error) {
+	tag, err := m.tag()
+	if err != nil {
+		return "", err
+	}
+	var buf [envelopeSize]byte
+	buf[0] = tag
+	id.encode((*[totalSize]byte)(buf[1:]))
+	return string(buf[:]), nil
+}
+
+// StringEnvelope returns the self-describing form of id for version 0 and
+// LayoutTimeFirst, the package defaults. It cannot tell how id was built, so
+// use Generator.Envelope for IDs from a configured generator and
+// Meta.Envelope for other layouts. Consumers sharing the same configuration
+// can keep using String.
+func (id ID) StringEnvelope() string {
+	s, _ := Meta{}.Envelope(id)
+	return s
+}
+
+// Meta returns the envelope metadata describing the IDs g issues. It fails
+// when g uses WithBitLayout or WithLocation with anything but the defaults, as
+// no envelope version describes other field widths or locations yet.
+func (g *Generator) Meta() (Meta, error) {
+	if g.bits != defaultBitLayout {
+		return Meta{}, fmt.Errorf("miniulid: no envelope version describes bit layout %v", g.bits)
+	}
+	if g.location != time.UTC {
+		return Meta{}, fmt.Errorf("miniulid: no envelope version describes location %s", g.location)
+	}
+	return Meta{Layout: g.layout}, nil
+}
+
+// Envelope returns the self-describing form of an ID issued by g, tagged with
+// g's Meta. It fails where Meta does, rather than stamp the ID with a
+// configuration it was not built with.
+func (g *Generator) Envelope(id ID) (string, error) {
+	m, err := g.Meta()
+	if err != nil {
+		return "", err
+	}
+	return m.Envelope(id)
+}
+
+// ParseEnvelope decodes a string produced by StringEnvelope, Meta.Envelope, or
+// Generator.Envelope, returning the ID and the metadata from its tag so
+// callers can detect a layout or epoch they do not expect. The ID is validated
+// with Layout.Parse for the tagged layout; versions other than 0 are rejected.
+// Like the ID, the tag must be canonical under ParseStrictMode.
+func ParseEnvelope(s string) (ID, Meta, error) {
+	if len(s) != envelopeSize {
+		return 0, Meta{}, fmt.Errorf("miniulid: envelope must be %d characters", envelopeSize)
+	}
+	v, ok := decodeAlphabet[s[0]]
+	if !ok || CurrentParseMode() == ParseStrictMode && encodeAlphabet[v] != s[0] {
+		return 0, Meta{}, fmt.Errorf("%w: envelope tag %q", errInvalidChar, s[0])
+	}
+	meta := Meta{Version: v >> 1, Layout: Layout(v & 1)}
+	if meta.Version != 0 {
+		return 0, meta, fmt.Errorf("miniulid: unsupported envelope version %d", meta.Version)
+	}
+
+	id, err := meta.Layout.Parse(s[1:])
+	if err != nil {
+		return 0, meta, err
+	}
+	return id, meta, nil
+}
@@ -0,0 +1,94 @@
+package miniulid
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEnvelopeRoundTrip(t *testing.T) {
+	id := ID(56755782866)
+	s := id.StringEnvelope()
+	if s != "0"+id.String() {
+		t.Fatalf("StringEnvelope: got %q", s)
+	}
+	got, meta, err := ParseEnvelope(s)
+	if err != nil || got != id || meta != (Meta{}) {
+		t.Fatalf("ParseEnvelope(%q): got %v, %+v, %v", s, got, meta, err)
+	}
+	if _, _, err := ParseEnvelope(strings.ToLower(s)); err != nil {
+		t.Fatalf("ParseEnvelope lowercase error: %v", err)
+	}
+
+	g := NewGenerator(WithLayout(LayoutEntropyFirst))
+	entropyFirst, err := g.generate(time.Date(2024, 8, 18, 15, 30, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("generate error: %v", err)
+	}
+	want := Meta{Layout: LayoutEntropyFirst}
+	s, err = want.Envelope(entropyFirst)
+	if err != nil {
+		t.Fatalf("Envelope error: %v", err)
+	}
+	got, meta, err = ParseEnvelope(s)
+	if err != nil || got != entropyFirst || meta != want {
+		t.Fatalf("ParseEnvelope(%q): got %v, %+v, %v", s, got, meta, err)
+	}
+}
+
+func TestGeneratorEnvelope(t *testing.T) {
+	now := time.Date(2024, 8, 18, 15, 30, 0, 0, time.UTC)
+	g := NewGenerator(WithLayout(LayoutEntropyFirst))
+	id, err := g.generate(now)
+	if err != nil {
+		t.Fatalf("generate error: %v", err)
+	}
+	s, err := g.Envelope(id)
+	if err != nil {
+		t.Fatalf("Envelope error: %v", err)
+	}
+	got, meta, err := ParseEnvelope(s)
+	if err != nil || got != id || meta != (Meta{Layout: LayoutEntropyFirst}) {
+		t.Fatalf("ParseEnvelope(%q): got %v, %+v, %v", s, got, meta, err)
+	}
+
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		t.Fatalf("LoadLocation error: %v", err)
+	}
+	for _, g := range []*Generator{
+		NewGenerator(WithBitLayout(13, 11, 16)),
+		NewGenerator(WithLocation(kolkata)),
+	} {
+		id, err := g.generate(now)
+		if err != nil {
+			t.Fatalf("generate error: %v", err)
+		}
+		if s, err := g.Envelope(id); err == nil {
+			t.Fatalf("Envelope stamped a non-default configuration as %q", s)
+		}
+	}
+}
+
+func TestEnvelopeErrors(t *testing.T) {
+	if _, err := (Meta{Version: 16}).Envelope(1); err == nil {
+		t.Fatalf("expected error for version 16")
+	}
+	if _, err := (Meta{Layout: 2}).Envelope(1); err == nil {
+		t.Fatalf("expected error for an unknown layout")
+	}
+
+	id := ID(56755782866)
+	v1, err := Meta{Version: 1}.Envelope(id)
+	if err != nil {
+		t.Fatalf("Envelope error: %v", err)
+	}
+	if _, meta, err := ParseEnvelope(v1); err == nil || meta.Version != 1 {
+		t.Fatalf("expected version 1 to be reported and rejected, got %+v, %v", meta, err)
+	}
+	for _, s := range []string{id.String(), "U" + id.String(), "0" + id.String() + "0"} {
+		if _, _, err := ParseEnvelope(s); err == nil {
+			t.Fatalf("ParseEnvelope(%q): expected error", s)
+		}
+	}
+}
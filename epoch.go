@@ -0,0 +1,41 @@
+package miniulid
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DaysSinceEpoch returns the raw day field.
+func (id ID) DaysSinceEpoch() uint16 {
+	return id.DayBucket()
+}
+
+// GuessEpoch solves for the epoch of IDs generated by another deployment,
+// given the true creation time of each ID. Each pair yields a candidate by
+// subtracting the ID's day and minute fields from its creation time; the
+// candidates must all agree.
+func GuessEpoch(ids []ID, knownTimes []time.Time) (time.Time, error) {
+	if len(ids) != len(knownTimes) {
+		return time.Time{}, fmt.Errorf("miniulid: %d IDs but %d times", len(ids), len(knownTimes))
+	}
+	if len(ids) == 0 {
+		return time.Time{}, errors.New("miniulid: no IDs to guess the epoch from")
+	}
+
+	var guess time.Time
+	for i, id := range ids {
+		days, minuteOfDay, _ := id.Components()
+		candidate := knownTimes[i].UTC().Truncate(time.Minute).
+			Add(-time.Duration(minuteOfDay)*time.Minute).
+			AddDate(0, 0, -int(days))
+
+		if i == 0 {
+			guess = candidate
+		} else if !candidate.Equal(guess) {
+			return time.Time{}, fmt.Errorf("miniulid: ID %d implies epoch %s, ID 0 implies %s",
+				i, candidate.Format(time.RFC3339), guess.Format(time.RFC3339))
+		}
+	}
+	return guess, nil
+}
@@ -0,0 +1,48 @@
+package miniulid
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGuessEpoch(t *testing.T) {
+	foreignEpoch := time.Date(2015, 6, 1, 0, 0, 0, 0, time.UTC)
+	created := []time.Time{
+		time.Date(2015, 6, 1, 0, 0, 10, 0, time.UTC),
+		time.Date(2019, 2, 3, 4, 5, 59, 0, time.UTC),
+		time.Date(2024, 8, 18, 15, 30, 0, 0, time.FixedZone("X", 3600)),
+	}
+
+	ids := make([]ID, len(created))
+	for i, ts := range created {
+		utc := ts.UTC()
+		days := utc.Truncate(24*time.Hour).Sub(foreignEpoch) / (24 * time.Hour)
+		id, err := FromComponents(uint16(days), uint16(utc.Hour()*60+utc.Minute()), uint16(i))
+		if err != nil {
+			t.Fatalf("FromComponents error: %v", err)
+		}
+		if got := id.DaysSinceEpoch(); got != uint16(days) {
+			t.Fatalf("DaysSinceEpoch: got %d want %d", got, days)
+		}
+		ids[i] = id
+	}
+
+	guess, err := GuessEpoch(ids, created)
+	if err != nil {
+		t.Fatalf("GuessEpoch error: %v", err)
+	}
+	if !guess.Equal(foreignEpoch) {
+		t.Fatalf("GuessEpoch: got %v want %v", guess, foreignEpoch)
+	}
+
+	created[1] = created[1].AddDate(0, 0, 1)
+	if _, err := GuessEpoch(ids, created); err == nil {
+		t.Fatalf("expected error for disagreeing IDs")
+	}
+	if _, err := GuessEpoch(ids, created[:1]); err == nil {
+		t.Fatalf("expected error for mismatched lengths")
+	}
+	if _, err := GuessEpoch(nil, nil); err == nil {
+		t.Fatalf("expected error for empty input")
+	}
+}
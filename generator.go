@@ -0,0 +1,796 @@
+package miniulid
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var defaultGenerator atomic.Pointer[Generator]
+
+// ErrCounterOverflow is returned when every counter value of the current minute
+// has been issued. The error names the minute; callers can wait for the next
+// minute and retry.
+var ErrCounterOverflow = errors.New("miniulid: counter overflow")
+
+// OverflowError is the error returned when a minute's counter is exhausted.
+// It wraps ErrCounterOverflow.
+type OverflowError struct {
+	// Minute is the minute whose counter is exhausted.
+	Minute time.Time
+	// Excess counts the requests of that minute refused for lack of counter
+	// values, this one included.
+	Excess int
+}
+
+func (e *OverflowError) Error() string {
+	return fmt.Sprintf("%v for minute %s (excess %d)", ErrCounterOverflow, e.Minute.Format(time.RFC3339), e.Excess)
+}
+
+func (e *OverflowError) Unwrap() error {
+	return ErrCounterOverflow
+}
+
+// ErrRateLimited is returned when a generator configured with WithRateLimit
+// has issued its quota for the current minute.
+var ErrRateLimited = errors.New("miniulid: per-minute rate limit reached")
+
+func init() {
+	defaultGenerator.Store(NewGenerator())
+}
+
+// DefaultGenerator returns the generator used by Generate and MustGenerate.
+func DefaultGenerator() *Generator {
+	return defaultGenerator.Load()
+}
+
+// SetDefaultGenerator replaces the generator used by Generate and MustGenerate.
+// A nil g installs a fresh generator with default options. The new generator
+// starts with its own counter, so swapping it after IDs have been issued may
+// produce IDs that collide with or sort before earlier ones; call it during
+// startup.
+func SetDefaultGenerator(g *Generator) {
+	if g == nil {
+		g = NewGenerator()
+	}
+	defaultGenerator.Store(g)
+}
+
+// Generator issues IDs from its own per-minute counter. It is safe for
+// concurrent use.
+type Generator struct {
+	counter minuteCounter
+
+	entropyMu sync.Mutex
+	entropy   io.Reader
+
+	layout   Layout
+	bits     BitLayout
+	skew     time.Duration
+	observer Observer
+	location *time.Location
+	now      func() time.Time
+
+	backfillMu sync.Mutex
+	backfill   backfillCounters
+}
+
+// Observer receives generator events, for example to feed metrics. Methods
+// are called synchronously, some while the generator's counter is locked, so
+// they must be fast and must not call back into the generator.
+type Observer interface {
+	// OnGenerate is called for every ID issued.
+	OnGenerate(id ID)
+	// OnMinuteReset is called when the counter starts a new minute.
+	OnMinuteReset(minute time.Time)
+	// OnOverflow is called when a minute's counter is exhausted, whether the
+	// generator then fails or moves on to a later minute.
+	OnOverflow(minute time.Time)
+}
+
+// Option configures a Generator.
+type Option func(*Generator)
+
+// WithMonotonicClock makes the generator ignore backward clock steps. When the
+// clock reports a minute earlier than the last one used, the generator keeps
+// issuing IDs from the last minute's counter so that IDs never go backward.
+func WithMonotonicClock() Option {
+	return func(g *Generator) {
+		g.counter.monotonic = true
+	}
+}
+
+// WithEntropy makes the generator fill the counter segment from r, as
+// GenerateWithTime does, instead of using the per-minute counter. A seeded
+// reader such as a math/rand source yields reproducible IDs. Random counters
+// are not monotonic and may collide within a minute. Without a counter there
+// is no per-minute count to cap, so NewGenerator panics if WithEntropy is
+// combined with WithRateLimit or WithCounterCeiling.
+func WithEntropy(r io.Reader) Option {
+	return func(g *Generator) {
+		g.entropy = r
+	}
+}
+
+// WithLayout sets the field order of generated IDs. See Layout.
+func WithLayout(l Layout) Option {
+	return func(g *Generator) {
+		g.layout = l
+	}
+}
+
+// WithCounterStart makes each new minute's counter begin at fn() instead of 0,
+// so the counter does not reveal how many IDs were issued that minute. Values
+// wrap within the counter range and stay unique until every value of the
+// minute has been used. Because of the wrap, IDs within a minute are no longer
+// ordered by issue time.
+func WithCounterStart(fn func() uint16) Option {
+	return func(g *Generator) {
+		g.counter.start = fn
+	}
+}
+
+// WithMinuteSkew tolerates clocks that run up to d behind: a time within d
+// before a minute boundary is attributed to the minute after the boundary.
+// Nodes whose clocks differ by less than d then agree on the minute near
+// boundaries. This is a best-effort ordering aid, not a guarantee, and d should
+// be well under a minute.
+func WithMinuteSkew(d time.Duration) Option {
+	return func(g *Generator) {
+		g.skew = d
+	}
+}
+
+// WithBorrowFutureMinutes lets the generator absorb bursts: when the current
+// minute's counter is exhausted it continues in the following minute, up to max
+// minutes ahead of the clock, instead of failing. The borrowed minutes are a
+// bounded time debt; IDs keep their order and the generator does not return to
+// the real minute until the clock catches up. Like WithMonotonicClock, this
+// also ignores backward clock steps.
+func WithBorrowFutureMinutes(max int) Option {
+	return func(g *Generator) {
+		g.counter.borrow = max
+	}
+}
+
+// WithObserver registers obs to receive the generator's events.
+func WithObserver(obs Observer) Option {
+	return func(g *Generator) {
+		g.observer = obs
+		g.counter.observer = obs
+	}
+}
+
+// WithReservedBits fixes the high n bits of every counter to value, for
+// example to tag IDs with a format version. Each minute then holds only
+// 2^(14-n) IDs. It panics unless value fits in n bits, and NewGenerator panics
+// unless n is below the counter width. Read the tag back with ID.ReservedBits.
+func WithReservedBits(n uint8, value uint16) Option {
+	if value>>n != 0 {
+		panic(fmt.Sprintf("miniulid: reserved value %d does not fit in %d bits", value, n))
+	}
+	return func(g *Generator) {
+		g.counter.reserved = n
+		g.counter.tag = value
+	}
+}
+
+// WithRandomNodePrefix is WithReservedBits with a value drawn from
+// crypto/rand when the generator is created, so that processes started
+// without coordination, such as ephemeral containers, usually issue distinct
+// IDs within the same minute. Prefixes are not guaranteed distinct: by the
+// birthday bound, k generators share at least one prefix with probability
+// about k²/2^(bits+1), roughly 1 in 5 for 20 generators with 10 bits, and
+// generators sharing a prefix can issue the same IDs. More bits lower that
+// risk but leave only 2^(14-bits) IDs per minute for each generator.
+func WithRandomNodePrefix(bits uint8) Option {
+	return func(g *Generator) {
+		var b [2]byte
+		rand.Read(b[:])
+		g.counter.reserved = bits
+		g.counter.tag = (uint16(b[0])<<8 | uint16(b[1])) & (1<<bits - 1)
+	}
+}
+
+// WithLocation makes the generator derive the day and minute fields from the
+// wall clock in loc instead of UTC; Generator.Time reverses this. The default
+// is UTC. IDs generated with different locations are not comparable, and the
+// package-level ID methods such as ID.Time still assume UTC.
+//
+// The counter follows the wall clock, which repeats an hour when daylight
+// saving time ends. Rather than reissue that hour's IDs, the generator stays
+// on the last wall-clock minute it issued from until the clock passes it
+// again, as under WithMonotonicClock, so Generate may fail with
+// ErrCounterOverflow during the repeated hour once that minute is full.
+// Generator.Time reports the first occurrence of a repeated wall-clock time.
+// Minutes skipped when daylight saving time starts are never issued.
+func WithLocation(loc *time.Location) Option {
+	return func(g *Generator) {
+		g.location = loc
+		g.counter.wall = loc != time.UTC
+	}
+}
+
+// WithRateLimit caps the IDs issued per minute at maxPerMinute, independently
+// of the counter capacity. Further calls in the same minute fail with
+// ErrRateLimited until the next minute. Zero means no limit.
+func WithRateLimit(maxPerMinute uint16) Option {
+	return func(g *Generator) {
+		g.counter.limit = maxPerMinute
+	}
+}
+
+// WithCounterCeiling treats a minute as exhausted once its counter has issued
+// n+1 values, leaving the values above n unused as headroom. From then on the
+// generator behaves as on a real overflow: Generate fails with
+// ErrCounterOverflow, or moves to a future minute under
+// WithBorrowFutureMinutes, and GenerateMany rolls to the next minute.
+// NewGenerator panics if n exceeds the counter range.
+func WithCounterCeiling(n uint16) Option {
+	return func(g *Generator) {
+		g.counter.ceiling = n
+		g.counter.ceilingSet = true
+	}
+}
+
+// WithBitLayout sets the widths of the day, minute, and counter fields, for
+// example 13/11/16 for 65536 IDs per minute with dates up to 2042. It panics
+// if the widths are invalid; see NewBitLayout. IDs from generators with
+// different bit layouts are incompatible, and the ID methods assume the
+// default layout: decode custom-layout IDs with Generator.Time or the
+// BitLayout methods.
+func WithBitLayout(daysBits, minutesBits, counterBits uint8) Option {
+	b, err := NewBitLayout(daysBits, minutesBits, counterBits)
+	if err != nil {
+		panic(err.Error())
+	}
+	return func(g *Generator) {
+		g.bits = b
+	}
+}
+
+// WithNow makes the generator read the time from now instead of time.Now,
+// for example to inject a fake clock. The returned times may carry any
+// location: only the instant matters, so daylight saving transitions in that
+// location neither skip nor repeat minutes. The generator's own location, UTC
+// unless set by WithLocation, is what decides the fields.
+func WithNow(now func() time.Time) Option {
+	return func(g *Generator) {
+		g.now = now
+	}
+}
+
+// NewGenerator returns a Generator configured with opts.
+func NewGenerator(opts ...Option) *Generator {
+	g := &Generator{location: time.UTC, bits: defaultBitLayout, now: time.Now}
+	g.backfill.max = defaultBackfillMinutes
+	g.counter.epoch = epoch
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	if g.entropy != nil && (g.counter.limit > 0 || g.counter.ceilingSet) {
+		panic("miniulid: WithEntropy cannot be combined with WithRateLimit or WithCounterCeiling")
+	}
+	g.counter.width = g.bits.counter
+	g.counter.location = g.location
+	mask := g.bits.CounterMask()
+	if g.counter.reserved >= g.counter.width {
+		panic(fmt.Sprintf("miniulid: reserved bits %d must be below %d", g.counter.reserved, g.counter.width))
+	}
+	if !g.counter.ceilingSet {
+		g.counter.ceiling = mask
+	} else if g.counter.ceiling > mask {
+		panic(fmt.Sprintf("miniulid: counter ceiling %d exceeds %d", g.counter.ceiling, mask))
+	}
+	return g
+}
+
+// Generate produces a new ID using the current UTC minute and the generator's
+// counter. It never returns ID(0) for the epoch minute, so a zero ID can
+// safely mean "unset".
+func (g *Generator) Generate() (ID, error) {
+	return g.generate(g.now())
+}
+
+// MustGenerate is like Generate but panics on error, for default-value hooks
+// that cannot return an error.
+func (g *Generator) MustGenerate() ID {
+	id, err := g.Generate()
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+func (g *Generator) generate(now time.Time) (ID, error) {
+	id, err := g.generateTimeFirst(now)
+	if err != nil {
+		return 0, err
+	}
+	id = g.layout.pack(id, g.bits.counter)
+	if g.observer != nil {
+		g.observer.OnGenerate(id)
+	}
+	return id, nil
+}
+
+func (g *Generator) generateTimeFirst(now time.Time) (ID, error) {
+	now = now.Add(g.skew)
+	if g.entropy != nil {
+		g.entropyMu.Lock()
+		defer g.entropyMu.Unlock()
+		counter, err := readCounter(g.entropy)
+		if err != nil {
+			return 0, err
+		}
+		counter = counter&g.counter.usableMask() | g.counter.tag<<(g.counter.width-g.counter.reserved)
+		id, err := g.fromComponents(g.wallMinute(now), counter)
+		if id == 0 && err == nil {
+			// Counter 0 of the epoch minute is ID(0), reserved as the zero
+			// value; counter 1 is the nearest usable value.
+			id = 1
+		}
+		return id, err
+	}
+
+	minute, counter, err := g.counter.next(g.wallMinute(now))
+	if err != nil {
+		return 0, err
+	}
+	return g.fromComponents(minute, counter)
+}
+
+// fromComponents builds a LayoutTimeFirst ID in the generator's bit layout
+// from a minute returned by wallMinute.
+func (g *Generator) fromComponents(minute time.Time, counter uint16) (ID, error) {
+	days, minuteOfDay, err := splitTime(minute)
+	if err != nil {
+		return 0, err
+	}
+	if uint64(days) >= 1<<g.bits.days {
+		return 0, errTimeFuture
+	}
+	return g.bits.FromComponents(days, minuteOfDay, counter)
+}
+
+// components returns the fields of an ID issued by g.
+func (g *Generator) components(id ID) (days uint16, minuteOfDay uint16, counter uint16) {
+	return g.bits.Components(g.layout.unpack(id, g.bits.counter))
+}
+
+// Time returns the time of an ID issued by g, interpreted with the
+// generator's layout, bit layout, and location.
+func (g *Generator) Time(id ID) time.Time {
+	days, minuteOfDay, _ := g.components(id)
+	return time.Date(2020, time.January, 1+int(days), 0, int(minuteOfDay), 0, 0, g.location)
+}
+
+// Capacity returns how many IDs g can issue per minute under its
+// configuration: the counter width of its BitLayout, less any WithReservedBits
+// or WithRandomNodePrefix bits, capped by WithCounterCeiling and
+// WithRateLimit. It is an int because a 16-bit counter holds 65536 values.
+// The epoch minute holds one fewer, as ID(0) is never issued.
+func (g *Generator) Capacity() int {
+	mc := &g.counter
+	n := int(min(mc.usableMask(), mc.ceiling)) + 1
+	if mc.limit > 0 {
+		n = min(n, int(mc.limit))
+	}
+	return n
+}
+
+// GenerateMany returns n unique IDs from the generator's counter, ascending
+// under LayoutTimeFirst unless WithCounterStart rotates the counter. When a
+// minute's counter is exhausted it moves on to the following minute without
+// waiting, so large batches carry Time values ahead of the real clock.
+// Later calls never reuse those minutes: until the clock catches up, Generate
+// and GenerateMany continue from the last minute the batch issued, and
+// Generate fails with ErrCounterOverflow if that minute is full.
+func (g *Generator) GenerateMany(n int) ([]ID, error) {
+	return g.generateMany(g.now(), n)
+}
+
+func (g *Generator) generateMany(now time.Time, n int) ([]ID, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("miniulid: negative batch size %d", n)
+	}
+
+	now = g.wallMinute(now.Add(g.skew))
+
+	mc := &g.counter
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	realMinute := now
+	if mc.minute.After(now) {
+		now = mc.minute
+	}
+	defer func() {
+		mc.ahead = mc.minute.After(realMinute)
+	}()
+
+	ids := make([]ID, 0, n)
+	for len(ids) < n {
+		minute, counter, err := mc.nextLocked(now)
+		if errors.Is(err, ErrCounterOverflow) {
+			now = mc.minute.Add(time.Minute)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		id, err := g.fromComponents(minute, counter)
+		if err != nil {
+			return nil, err
+		}
+		id = g.layout.pack(id, g.bits.counter)
+		if g.observer != nil {
+			g.observer.OnGenerate(id)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// Resume continues the counter after lastIssued, typically the highest ID
+// issued before a restart, so the next IDs of the same minute do not collide
+// with it. It is a no-op when lastIssued belongs to another minute or when the
+// counter is already past it. It fails under WithCounterStart, since one ID
+// does not reveal the minute's start value and so which values were issued.
+func (g *Generator) Resume(lastIssued ID) error {
+	return g.resume(g.now(), lastIssued)
+}
+
+func (g *Generator) resume(now time.Time, lastIssued ID) error {
+	if g.counter.start != nil {
+		return fmt.Errorf("miniulid: cannot resume from %s under WithCounterStart", lastIssued)
+	}
+	days, minuteOfDay, counter := g.components(lastIssued)
+	if minuteOfDay >= minutesPerDay {
+		return fmt.Errorf("miniulid: cannot resume from %s: %w: %d", lastIssued, ErrInvalidMinute, minuteOfDay)
+	}
+
+	minute := time.Date(2020, time.January, 1+int(days), 0, int(minuteOfDay), 0, 0, time.UTC)
+	if !minute.Equal(g.clockMinute(now)) {
+		return nil
+	}
+	g.counter.continueFrom(minute, counter)
+	return nil
+}
+
+// GeneratorState is a snapshot of a generator's counter, suitable for
+// persisting across restarts.
+type GeneratorState struct {
+	Minute  time.Time `json:"minute"`
+	Counter uint16    `json:"counter"`
+}
+
+// State returns the minute and counter value of the last ID issued. Minute is
+// zero if the generator has not issued any ID.
+func (g *Generator) State() GeneratorState {
+	mc := &g.counter
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	if mc.minute.IsZero() {
+		return GeneratorState{}
+	}
+	return GeneratorState{Minute: mc.inLocation(mc.minute), Counter: mc.value()}
+}
+
+// CurrentCounter returns the minute the generator is issuing from and how
+// many IDs it has issued in that minute, without advancing the counter, for
+// example to report on a health endpoint how full the minute is relative to
+// Capacity. Minute is zero if the generator has not issued any ID. Unlike
+// State, the count does not include values skipped by Resume or RestoreState.
+// It is an int, like Capacity, because a 16-bit counter holds 65536 values.
+func (g *Generator) CurrentCounter() (minute time.Time, issued int) {
+	mc := &g.counter
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	return mc.inLocation(mc.minute), mc.issued
+}
+
+// RestoreState continues from a snapshot taken with State. A snapshot of the
+// current minute continues its counter, one of an earlier minute is ignored,
+// and one of a later minute is refused since the generator would otherwise
+// issue IDs that sort before ones already handed out. Under WithCounterStart a
+// snapshot of the current minute is refused too: the counter may have wrapped
+// past the start value, which the snapshot does not record.
+func (g *Generator) RestoreState(state GeneratorState) error {
+	return g.restoreState(g.now(), state)
+}
+
+func (g *Generator) restoreState(now time.Time, state GeneratorState) error {
+	minute := g.wallMinute(state.Minute)
+	current := g.clockMinute(now)
+	switch {
+	case minute.After(current):
+		return fmt.Errorf("miniulid: state minute %s is ahead of the clock (%s)",
+			minute.Format(time.RFC3339), current.Format(time.RFC3339))
+	case minute.Equal(current):
+		if g.counter.start != nil {
+			return fmt.Errorf("miniulid: cannot restore the counter of the current minute %s under WithCounterStart",
+				minute.Format(time.RFC3339))
+		}
+		g.counter.continueFrom(minute, state.Counter)
+	}
+	return nil
+}
+
+// Reset discards the counter state so the next ID starts a fresh minute at
+// counter 0, as for a new generator; options are kept. IDs issued after a
+// reset may collide with earlier ones of the same minute, so it is meant for
+// isolating tests that share a generator.
+func (g *Generator) Reset() {
+	g.counter.clear()
+}
+
+// ResetDefault resets the default generator. See Generator.Reset.
+func ResetDefault() {
+	DefaultGenerator().Reset()
+}
+
+// clockMinute returns the minute the generator attributes now to.
+func (g *Generator) clockMinute(now time.Time) time.Time {
+	return g.wallMinute(now.Add(g.skew))
+}
+
+// wallMinute returns the wall-clock minute of t in the generator's location,
+// expressed in UTC. The counter is keyed by these minutes, so that it follows
+// the day and minute fields even where the location repeats an hour.
+func (g *Generator) wallMinute(t time.Time) time.Time {
+	t = t.In(g.location)
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, time.UTC)
+}
+
+// inLocation reverses Generator.wallMinute for the minutes the counter
+// reports, leaving the zero time unchanged.
+func (mc *minuteCounter) inLocation(minute time.Time) time.Time {
+	if minute.IsZero() {
+		return minute
+	}
+	return time.Date(minute.Year(), minute.Month(), minute.Day(), minute.Hour(), minute.Minute(), 0, 0, mc.location)
+}
+
+type minuteCounter struct {
+	mu     sync.Mutex
+	minute time.Time
+	// first is the earliest minute issued from since the last clear.
+	first  time.Time
+	offset uint16
+	index  uint16
+
+	monotonic bool
+	// wall is set under WithLocation, whose wall clock may step back.
+	wall bool
+	// location is the generator's location, in which the counter's minutes
+	// are wall-clock minutes.
+	location *time.Location
+	// ahead is set while a batch has left minute past the clock, so that
+	// later calls stay on it instead of reusing the clock's minute.
+	ahead    bool
+	start    func() uint16
+	borrow   int
+	observer Observer
+
+	// width is the number of counter bits, set from the generator's
+	// BitLayout.
+	width    uint8
+	reserved uint8
+	tag      uint16
+
+	// epoch is the minute whose counter 0 encodes as ID(0).
+	epoch time.Time
+
+	limit  uint16
+	issued int
+
+	// excess counts advances refused in the current minute.
+	excess int
+
+	// ceiling is the highest counter index issued per minute.
+	ceiling    uint16
+	ceilingSet bool
+}
+
+// next returns the minute to encode and its counter value. With monotonic set,
+// a minute earlier than the last one is clamped to the last one.
+func (mc *minuteCounter) next(t time.Time) (time.Time, uint16, error) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	return mc.nextLocked(t)
+}
+
+func (mc *minuteCounter) nextLocked(t time.Time) (time.Time, uint16, error) {
+	realMinute := t.UTC().Truncate(time.Minute)
+	currentMinute := realMinute
+	if (mc.monotonic || mc.wall || mc.ahead || mc.borrow > 0) && currentMinute.Before(mc.minute) {
+		currentMinute = mc.minute
+	}
+
+	if mc.minute.IsZero() || !mc.minute.Equal(currentMinute) {
+		mc.reset(currentMinute)
+	} else if mc.limit > 0 && mc.issued >= int(mc.limit) {
+		return time.Time{}, 0, fmt.Errorf("%w: %d IDs issued for minute %s", ErrRateLimited, mc.issued, mc.inLocation(mc.minute).Format(time.RFC3339))
+	} else if err := mc.advance(); err != nil {
+		limit := realMinute.Add(time.Duration(mc.borrow) * time.Minute)
+		if !mc.minute.Before(limit) {
+			return time.Time{}, 0, err
+		}
+		currentMinute = mc.minute.Add(time.Minute)
+		mc.reset(currentMinute)
+	}
+
+	value := mc.value()
+	if value == 0 && currentMinute.Equal(mc.epoch) {
+		// Counter 0 of the epoch minute is ID(0), reserved as the zero value.
+		if err := mc.advance(); err != nil {
+			return time.Time{}, 0, err
+		}
+		value = mc.value()
+	}
+	mc.issued++
+	return currentMinute, value, nil
+}
+
+// continueFrom moves the counter past counter in minute, unless it already is.
+func (mc *minuteCounter) continueFrom(minute time.Time, counter uint16) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	if !mc.minute.Equal(minute) {
+		mc.minute = minute
+		mc.noteFirst(minute)
+		mc.offset = 0
+		mc.index = counter & mc.usableMask()
+		mc.issued = 0
+		mc.excess = 0
+		mc.ahead = false
+		return
+	}
+	if index := (counter - mc.offset) & mc.usableMask(); index > mc.index {
+		mc.index = index
+	}
+}
+
+// clear forgets the current minute.
+func (mc *minuteCounter) clear() {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	mc.minute = time.Time{}
+	mc.first = time.Time{}
+	mc.ahead = false
+	mc.offset = 0
+	mc.index = 0
+	mc.issued = 0
+	mc.excess = 0
+}
+
+// reset starts the counter for a new minute.
+func (mc *minuteCounter) reset(minute time.Time) {
+	mc.minute = minute
+	mc.noteFirst(minute)
+	mc.ahead = false
+	mc.offset = 0
+	if mc.start != nil {
+		mc.offset = mc.start() & mc.usableMask()
+	}
+	mc.index = 0
+	mc.issued = 0
+	mc.excess = 0
+	if mc.observer != nil {
+		mc.observer.OnMinuteReset(mc.inLocation(minute))
+	}
+}
+
+func (mc *minuteCounter) noteFirst(minute time.Time) {
+	if mc.first.IsZero() || minute.Before(mc.first) {
+		mc.first = minute
+	}
+}
+
+// advance moves to the next counter value of the current minute, failing once
+// every value has been issued.
+func (mc *minuteCounter) advance() error {
+	if mc.index >= min(mc.usableMask(), mc.ceiling) {
+		if mc.observer != nil {
+			mc.observer.OnOverflow(mc.inLocation(mc.minute))
+		}
+		mc.excess++
+		return &OverflowError{Minute: mc.inLocation(mc.minute), Excess: mc.excess}
+	}
+	mc.index++
+	return nil
+}
+
+func (mc *minuteCounter) value() uint16 {
+	usable := mc.usableMask()
+	return mc.tag<<(mc.width-mc.reserved) | (mc.offset+mc.index)&usable
+}
+
+// usableMask covers the counter bits not fixed by WithReservedBits.
+func (mc *minuteCounter) usableMask() uint16 {
+	return uint16(1<<mc.width-1) >> mc.reserved
+}
+
+// Stream returns a reader that yields newly generated IDs from g, each
+// followed by a newline. IDs are generated lazily, only as many as needed to
+// fill each Read, so a slow consumer never causes IDs to be issued ahead of
+// time; a partially read ID is finished on the next Read. Generation errors,
+// such as exhausting a minute's counter, are returned from Read.
+func (g *Generator) Stream() io.Reader {
+	return &idStream{g: g}
+}
+
+type idStream struct {
+	g       *Generator
+	buf     [totalSize + 1]byte
+	pending []byte
+}
+
+func (s *idStream) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if len(s.pending) == 0 {
+			id, err := s.g.Generate()
+			if err != nil {
+				return n, err
+			}
+			id.encode((*[totalSize]byte)(s.buf[:totalSize]))
+			s.buf[totalSize] = '\n'
+			s.pending = s.buf[:]
+		}
+		c := copy(p[n:], s.pending)
+		s.pending = s.pending[c:]
+		n += c
+	}
+	return n, nil
+}
+
+// GenerateUnique issues n IDs from g, spread across the given number of
+// goroutines, and returns them in no particular order. It fails if any
+// generation fails or any ID repeats, which makes it a ready-made uniqueness
+// check for concurrent use. A nil g uses the default generator.
+func GenerateUnique(g *Generator, n, goroutines int) ([]ID, error) {
+	if n < 0 || goroutines < 1 {
+		return nil, fmt.Errorf("miniulid: invalid stress parameters n=%d goroutines=%d", n, goroutines)
+	}
+	if g == nil {
+		g = DefaultGenerator()
+	}
+
+	ids := make([]ID, n)
+	errs := make([]error, goroutines)
+	var wg sync.WaitGroup
+	for w := range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := w; i < n; i += goroutines {
+				id, err := g.Generate()
+				if err != nil {
+					errs[w] = err
+					return
+				}
+				ids[i] = id
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	if dups := Duplicates(ids); len(dups) > 0 {
+		return nil, fmt.Errorf("miniulid: %d duplicate IDs, first %s", len(dups), dups[0])
+	}
+	return ids, nil
+}
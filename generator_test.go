@@ -0,0 +1,941 @@
+package miniulid
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMonotonicClock(t *testing.T) {
+	later := time.Date(2024, 8, 18, 15, 30, 0, 0, time.UTC)
+	earlier := later.Add(-2 * time.Minute)
+
+	g := NewGenerator(WithMonotonicClock())
+	first, err := g.generate(later)
+	if err != nil {
+		t.Fatalf("generate error: %v", err)
+	}
+	second, err := g.generate(earlier)
+	if err != nil {
+		t.Fatalf("generate error: %v", err)
+	}
+	if second <= first {
+		t.Fatalf("ID went backward: %v after %v", second, first)
+	}
+	if !second.Time().Equal(later) {
+		t.Fatalf("clamped time: got %v want %v", second.Time(), later)
+	}
+
+	plain := NewGenerator()
+	first, _ = plain.generate(later)
+	second, _ = plain.generate(earlier)
+	if second >= first {
+		t.Fatalf("expected default generator to follow the clock backward")
+	}
+}
+
+func TestSetDefaultGenerator(t *testing.T) {
+	original := DefaultGenerator()
+	t.Cleanup(func() { SetDefaultGenerator(original) })
+
+	g := NewGenerator(WithMonotonicClock())
+	SetDefaultGenerator(g)
+	if DefaultGenerator() != g {
+		t.Fatalf("DefaultGenerator did not return the installed generator")
+	}
+	if _, err := Generate(); err != nil {
+		t.Fatalf("Generate error: %v", err)
+	}
+	if g.counter.minute.IsZero() {
+		t.Fatalf("Generate did not use the installed generator")
+	}
+
+	SetDefaultGenerator(nil)
+	if got := DefaultGenerator(); got == nil || got == g {
+		t.Fatalf("SetDefaultGenerator(nil) did not install a fresh generator")
+	}
+}
+
+func TestWithEntropy(t *testing.T) {
+	now := time.Date(2024, 8, 18, 15, 30, 0, 0, time.UTC)
+	run := func() []ID {
+		g := NewGenerator(WithEntropy(rand.New(rand.NewSource(42))))
+		ids := make([]ID, 5)
+		for i := range ids {
+			id, err := g.generate(now)
+			if err != nil {
+				t.Fatalf("generate error: %v", err)
+			}
+			ids[i] = id
+		}
+		return ids
+	}
+
+	first, second := run(), run()
+	if !slices.Equal(first, second) {
+		t.Fatalf("seeded runs differ: %v vs %v", first, second)
+	}
+	if !first[0].Time().Equal(now) {
+		t.Fatalf("time mismatch: got %v want %v", first[0].Time(), now)
+	}
+}
+
+func TestWithEntropyLimits(t *testing.T) {
+	g := NewGenerator(WithEntropy(bytes.NewReader(make([]byte, 2))))
+	if id, err := g.generate(epoch); err != nil || id == 0 {
+		t.Fatalf("generate with zero entropy at the epoch: got %v, %v", id, err)
+	}
+
+	for name, opt := range map[string]Option{
+		"WithRateLimit":      WithRateLimit(1),
+		"WithCounterCeiling": WithCounterCeiling(10),
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("expected panic combining WithEntropy and %s", name)
+				}
+			}()
+			NewGenerator(WithEntropy(rand.New(rand.NewSource(1))), opt)
+		}()
+	}
+}
+
+func TestStream(t *testing.T) {
+	scanner := bufio.NewScanner(NewGenerator().Stream())
+	ids := make([]ID, 0, 100)
+	for len(ids) < cap(ids) && scanner.Scan() {
+		id, err := Parse(scanner.Text())
+		if err != nil {
+			t.Fatalf("Parse(%q) error: %v", scanner.Text(), err)
+		}
+		ids = append(ids, id)
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if len(ids) != cap(ids) {
+		t.Fatalf("read %d IDs, want %d", len(ids), cap(ids))
+	}
+	if HasDuplicates(ids) {
+		t.Fatalf("stream produced duplicates")
+	}
+
+	// Reads smaller than one record must still reassemble whole IDs.
+	var buf [3]byte
+	var line []byte
+	s := NewGenerator().Stream()
+	for len(line) == 0 || line[len(line)-1] != '\n' {
+		n, err := s.Read(buf[:])
+		if err != nil {
+			t.Fatalf("Read error: %v", err)
+		}
+		line = append(line, buf[:n]...)
+	}
+	if _, err := Parse(string(line[:len(line)-1])); err != nil {
+		t.Fatalf("Parse(%q) error: %v", line, err)
+	}
+}
+
+func TestGenerateSkipsZeroID(t *testing.T) {
+	g := NewGenerator()
+	id, err := g.generate(epoch.Add(30 * time.Second))
+	if err != nil {
+		t.Fatalf("generate error: %v", err)
+	}
+	if id == 0 {
+		t.Fatalf("generator issued the reserved zero ID")
+	}
+	if _, _, counter := id.Components(); counter != 1 {
+		t.Fatalf("counter: got %d want 1", counter)
+	}
+
+	next, _ := g.generate(epoch)
+	if next != id+1 {
+		t.Fatalf("second ID: got %v want %v", next, id+1)
+	}
+}
+
+func TestGenerateMany(t *testing.T) {
+	now := time.Date(2024, 8, 18, 15, 30, 0, 0, time.UTC)
+	g := NewGenerator()
+	if _, err := g.generate(now); err != nil {
+		t.Fatalf("generate error: %v", err)
+	}
+
+	n := 2*(counterMask+1) + 10
+	ids, err := g.generateMany(now, n)
+	if err != nil {
+		t.Fatalf("generateMany error: %v", err)
+	}
+	if len(ids) != n {
+		t.Fatalf("generateMany count: got %d want %d", len(ids), n)
+	}
+	if !slices.IsSorted(ids) || HasDuplicates(ids) {
+		t.Fatalf("generateMany IDs are not strictly ascending")
+	}
+	if got, want := ids[len(ids)-1].Time(), now.Add(2*time.Minute); !got.Equal(want) {
+		t.Fatalf("last ID time: got %v want %v", got, want)
+	}
+
+	more, err := g.generateMany(now, 1)
+	if err != nil {
+		t.Fatalf("generateMany error: %v", err)
+	}
+	if more[0] <= ids[len(ids)-1] {
+		t.Fatalf("next batch went backward: %v after %v", more[0], ids[len(ids)-1])
+	}
+
+	next, err := g.generate(now)
+	if err != nil {
+		t.Fatalf("generate after batch error: %v", err)
+	}
+	if next <= more[0] {
+		t.Fatalf("Generate after batch went backward: %v after %v", next, more[0])
+	}
+	if later, err := g.generate(now.Add(3 * time.Minute)); err != nil || later <= next {
+		t.Fatalf("Generate once the clock passed the batch: got %v, %v after %v", later, err, next)
+	}
+
+	started := NewGenerator(WithCounterStart(func() uint16 { return 100 }))
+	batch, err := started.generateMany(now, counterMask+2)
+	if err != nil {
+		t.Fatalf("generateMany error: %v", err)
+	}
+	for i := range 3 {
+		id, err := started.generate(now)
+		if err != nil {
+			t.Fatalf("generate %d after batch error: %v", i, err)
+		}
+		batch = append(batch, id)
+	}
+	if HasDuplicates(batch) {
+		t.Fatalf("Generate after a batch reissued one of its IDs")
+	}
+
+	if _, err := g.GenerateMany(-1); err == nil {
+		t.Fatalf("expected error for negative batch size")
+	}
+}
+
+func TestWithCounterStart(t *testing.T) {
+	starts := []uint16{100, counterMask}
+	g := NewGenerator(WithCounterStart(func() uint16 {
+		start := starts[0]
+		starts = starts[1:]
+		return start
+	}))
+
+	now := time.Date(2024, 8, 18, 15, 30, 0, 0, time.UTC)
+	first, _ := g.generate(now)
+	second, _ := g.generate(now.Add(time.Minute))
+	wrapped, _ := g.generate(now.Add(time.Minute))
+
+	counter := func(id ID) uint16 {
+		_, _, c := id.Components()
+		return c
+	}
+	if got := counter(first); got != 100 {
+		t.Fatalf("first minute start: got %d want 100", got)
+	}
+	if got := counter(second); got != counterMask {
+		t.Fatalf("second minute start: got %d want %d", got, counterMask)
+	}
+	if got := counter(wrapped); got != 0 {
+		t.Fatalf("wrapped counter: got %d want 0", got)
+	}
+
+	ids, err := g.generateMany(now.Add(time.Minute), counterMask-1)
+	if err != nil {
+		t.Fatalf("generateMany error: %v", err)
+	}
+	if HasDuplicates(append(ids, second, wrapped)) {
+		t.Fatalf("counter repeated before lapping")
+	}
+	if !ids[len(ids)-1].Time().Equal(now.Add(time.Minute)) {
+		t.Fatalf("minute exhausted before lapping")
+	}
+	if _, err := g.generate(now.Add(time.Minute)); !errors.Is(err, ErrCounterOverflow) {
+		t.Fatalf("expected ErrCounterOverflow after lap, got %v", err)
+	}
+}
+
+func TestWithMinuteSkew(t *testing.T) {
+	boundary := time.Date(2024, 8, 18, 15, 30, 0, 0, time.UTC)
+	cases := []struct {
+		clock time.Time
+		want  time.Time
+	}{
+		{boundary.Add(-3 * time.Second), boundary},
+		{boundary.Add(3 * time.Second), boundary},
+		{boundary.Add(-10 * time.Second), boundary.Add(-time.Minute)},
+	}
+	for _, tc := range cases {
+		g := NewGenerator(WithMinuteSkew(5 * time.Second))
+		id, err := g.generate(tc.clock)
+		if err != nil {
+			t.Fatalf("generate error: %v", err)
+		}
+		if !id.Time().Equal(tc.want) {
+			t.Fatalf("clock %v: got minute %v want %v", tc.clock, id.Time(), tc.want)
+		}
+	}
+}
+
+func TestResume(t *testing.T) {
+	now := time.Date(2024, 8, 18, 15, 30, 0, 0, time.UTC)
+
+	before := NewGenerator()
+	var issued []ID
+	for range 5 {
+		id, err := before.generate(now)
+		if err != nil {
+			t.Fatalf("generate error: %v", err)
+		}
+		issued = append(issued, id)
+	}
+
+	// Simulate a crash: a fresh generator resumes from the last issued ID.
+	after := NewGenerator()
+	if err := after.resume(now.Add(20*time.Second), issued[len(issued)-1]); err != nil {
+		t.Fatalf("resume error: %v", err)
+	}
+	for range 5 {
+		id, err := after.generate(now)
+		if err != nil {
+			t.Fatalf("generate error: %v", err)
+		}
+		issued = append(issued, id)
+	}
+	if HasDuplicates(issued) || !slices.IsSorted(issued) {
+		t.Fatalf("resumed counters collide or go backward: %v", issued)
+	}
+
+	// Resuming from a past minute leaves the counter untouched.
+	stale := NewGenerator()
+	if err := stale.resume(now.Add(time.Minute), issued[len(issued)-1]); err != nil {
+		t.Fatalf("resume error: %v", err)
+	}
+	if id, _ := stale.generate(now.Add(time.Minute)); id.TruncateMinute() != id {
+		t.Fatalf("past-minute resume changed the counter: %s", id.Inspect())
+	}
+
+	if err := after.resume(now, ID(minutesPerDay)<<counterBits); !errors.Is(err, ErrInvalidMinute) {
+		t.Fatalf("expected ErrInvalidMinute, got %v", err)
+	}
+
+	// Resuming into a new minute starts that minute's rate limit afresh.
+	limited := NewGenerator(WithRateLimit(3))
+	for range 3 {
+		if _, err := limited.generate(now); err != nil {
+			t.Fatalf("generate error: %v", err)
+		}
+	}
+	next, err := GenerateWithComponents(now.Add(time.Minute), 5)
+	if err != nil {
+		t.Fatalf("GenerateWithComponents error: %v", err)
+	}
+	if err := limited.resume(now.Add(time.Minute), next); err != nil {
+		t.Fatalf("resume error: %v", err)
+	}
+	if _, err := limited.generate(now.Add(time.Minute)); err != nil {
+		t.Fatalf("generate after resuming a new minute error: %v", err)
+	}
+	if _, count := limited.CurrentCounter(); count != 1 {
+		t.Fatalf("CurrentCounter after resuming a new minute: got %d want 1", count)
+	}
+
+	started := NewGenerator(WithCounterStart(func() uint16 { return 16000 }))
+	if err := started.resume(now, issued[len(issued)-1]); err == nil {
+		t.Fatalf("expected error resuming under WithCounterStart")
+	}
+}
+
+func TestWithBorrowFutureMinutes(t *testing.T) {
+	now := time.Date(2024, 8, 18, 15, 30, 0, 0, time.UTC)
+	g := NewGenerator(WithBorrowFutureMinutes(1))
+
+	n := counterMask + 1 + 5
+	ids := make([]ID, 0, n)
+	for range n {
+		id, err := g.generate(now)
+		if err != nil {
+			t.Fatalf("generate error after %d IDs: %v", len(ids), err)
+		}
+		ids = append(ids, id)
+	}
+	if !slices.IsSorted(ids) || HasDuplicates(ids) {
+		t.Fatalf("borrowed IDs are not strictly ascending")
+	}
+	last := ids[len(ids)-1]
+	if !last.Time().Equal(now.Add(time.Minute)) {
+		t.Fatalf("last ID time: got %v want %v", last.Time(), now.Add(time.Minute))
+	}
+
+	// The real clock is still behind, so generation stays in the borrowed minute.
+	id, err := g.generate(now.Add(30 * time.Second))
+	if err != nil || id <= last {
+		t.Fatalf("generate after borrowing: got %v, %v", id, err)
+	}
+
+	if _, err := g.generateMany(now, counterMask-5); err != nil {
+		t.Fatalf("generateMany error: %v", err)
+	}
+	if _, err := g.generate(now); !errors.Is(err, ErrCounterOverflow) {
+		t.Fatalf("expected ErrCounterOverflow past the borrow limit, got %v", err)
+	}
+}
+
+type recordingObserver struct {
+	generated int
+	resets    []time.Time
+	overflows []time.Time
+}
+
+func (o *recordingObserver) OnGenerate(ID)                  { o.generated++ }
+func (o *recordingObserver) OnMinuteReset(minute time.Time) { o.resets = append(o.resets, minute) }
+func (o *recordingObserver) OnOverflow(minute time.Time)    { o.overflows = append(o.overflows, minute) }
+
+func TestWithObserver(t *testing.T) {
+	now := time.Date(2024, 8, 18, 15, 30, 0, 0, time.UTC)
+	obs := &recordingObserver{}
+	g := NewGenerator(WithObserver(obs))
+
+	if _, err := g.generateMany(now, counterMask+1); err != nil {
+		t.Fatalf("generateMany error: %v", err)
+	}
+	if _, err := g.generate(now); !errors.Is(err, ErrCounterOverflow) {
+		t.Fatalf("expected ErrCounterOverflow, got %v", err)
+	}
+	if _, err := g.generate(now.Add(time.Minute)); err != nil {
+		t.Fatalf("generate error: %v", err)
+	}
+
+	if obs.generated != counterMask+2 {
+		t.Fatalf("OnGenerate calls: got %d want %d", obs.generated, counterMask+2)
+	}
+	if len(obs.resets) != 2 || !obs.resets[0].Equal(now) || !obs.resets[1].Equal(now.Add(time.Minute)) {
+		t.Fatalf("OnMinuteReset calls: got %v", obs.resets)
+	}
+	if len(obs.overflows) != 1 || !obs.overflows[0].Equal(now) {
+		t.Fatalf("OnOverflow calls: got %v", obs.overflows)
+	}
+}
+
+func TestWithReservedBits(t *testing.T) {
+	now := time.Date(2024, 8, 18, 15, 30, 0, 0, time.UTC)
+	g := NewGenerator(WithReservedBits(2, 0b10))
+
+	capacity := 1 << (counterBits - 2)
+	ids, err := g.generateMany(now, capacity)
+	if err != nil {
+		t.Fatalf("generateMany error: %v", err)
+	}
+	for _, id := range ids {
+		if got := id.ReservedBits(2); got != 0b10 {
+			t.Fatalf("ReservedBits: got %b want 10 for %s", got, id.Inspect())
+		}
+	}
+	if HasDuplicates(ids) || !ids[len(ids)-1].Time().Equal(now) {
+		t.Fatalf("reserved-bit counters collide or spill into the next minute")
+	}
+	if _, err := g.generate(now); !errors.Is(err, ErrCounterOverflow) {
+		t.Fatalf("expected ErrCounterOverflow after %d IDs, got %v", capacity, err)
+	}
+
+	entropy := NewGenerator(WithReservedBits(1, 1), WithEntropy(bytes.NewReader([]byte{0, 0})))
+	id, err := entropy.generate(now)
+	if err != nil {
+		t.Fatalf("generate error: %v", err)
+	}
+	if got := id.ReservedBits(1); got != 1 {
+		t.Fatalf("entropy ReservedBits: got %d want 1", got)
+	}
+
+	for _, bad := range []func(){
+		func() { NewGenerator(WithReservedBits(counterBits, 0)) },
+		func() { WithReservedBits(2, 4) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("expected panic for invalid reserved bits")
+				}
+			}()
+			bad()
+		}()
+	}
+}
+
+func TestErrCounterOverflow(t *testing.T) {
+	now := time.Date(2024, 8, 18, 15, 30, 0, 0, time.UTC)
+	g := NewGenerator()
+	if _, err := g.generateMany(now, counterMask+1); err != nil {
+		t.Fatalf("generateMany error: %v", err)
+	}
+
+	_, err := g.generate(now)
+	if !errors.Is(err, ErrCounterOverflow) {
+		t.Fatalf("expected ErrCounterOverflow, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "2024-08-18T15:30:00Z") {
+		t.Fatalf("error lacks the minute: %v", err)
+	}
+	var overflow *OverflowError
+	if !errors.As(err, &overflow) || !overflow.Minute.Equal(now) || overflow.Excess != 1 {
+		t.Fatalf("expected *OverflowError for %v, got %#v", now, err)
+	}
+	_, err = g.generate(now)
+	if !errors.As(err, &overflow) || overflow.Excess != 2 {
+		t.Fatalf("second overflow: got %#v", err)
+	}
+
+	if _, err := g.generate(now.Add(time.Minute)); err != nil {
+		t.Fatalf("retry in the next minute failed: %v", err)
+	}
+}
+
+func TestStateRoundTrip(t *testing.T) {
+	now := time.Date(2024, 8, 18, 15, 30, 0, 0, time.UTC)
+
+	before := NewGenerator()
+	if state := before.State(); !state.Minute.IsZero() {
+		t.Fatalf("fresh generator state: got %+v", state)
+	}
+	issued, err := before.generateMany(now, 10)
+	if err != nil {
+		t.Fatalf("generateMany error: %v", err)
+	}
+
+	data, err := json.Marshal(before.State())
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	var state GeneratorState
+	if err := json.Unmarshal(data, &state); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if !state.Minute.Equal(now) || state.Counter != 9 {
+		t.Fatalf("State: got %+v", state)
+	}
+
+	after := NewGenerator()
+	if err := after.restoreState(now.Add(10*time.Second), state); err != nil {
+		t.Fatalf("restoreState error: %v", err)
+	}
+	more, err := after.generateMany(now, 10)
+	if err != nil {
+		t.Fatalf("generateMany error: %v", err)
+	}
+	if all := append(issued, more...); HasDuplicates(all) || !slices.IsSorted(all) {
+		t.Fatalf("restored generator collides or goes backward")
+	}
+
+	if err := NewGenerator().restoreState(now.Add(-time.Minute), state); err == nil {
+		t.Fatalf("expected error restoring a future state")
+	}
+	stale := NewGenerator()
+	if err := stale.restoreState(now.Add(time.Hour), state); err != nil {
+		t.Fatalf("restoreState of past minute error: %v", err)
+	}
+	if !stale.State().Minute.IsZero() {
+		t.Fatalf("past-minute state was applied")
+	}
+
+	// A started counter wraps, so its snapshot cannot say which values were
+	// issued.
+	start := func() uint16 { return 16000 }
+	started := NewGenerator(WithCounterStart(start))
+	if _, err := started.generateMany(now, 500); err != nil {
+		t.Fatalf("generateMany error: %v", err)
+	}
+	restarted := NewGenerator(WithCounterStart(start))
+	if err := restarted.restoreState(now, started.State()); err == nil {
+		t.Fatalf("expected error restoring a current-minute state under WithCounterStart")
+	}
+	if err := restarted.restoreState(now.Add(time.Minute), started.State()); err != nil {
+		t.Fatalf("restoreState of past minute under WithCounterStart error: %v", err)
+	}
+}
+
+func TestWithLocation(t *testing.T) {
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		t.Fatalf("LoadLocation error: %v", err)
+	}
+	g := NewGenerator(WithLocation(kolkata))
+
+	cases := []struct {
+		now     time.Time
+		minute  uint16
+		nextDay bool
+	}{
+		{time.Date(2024, 8, 18, 10, 0, 0, 0, time.UTC), 15*60 + 30, false},
+		{time.Date(2024, 8, 18, 20, 0, 0, 0, time.UTC), 1*60 + 30, true},
+	}
+	utcDays, _, _ := splitTime(cases[0].now)
+	for _, tc := range cases {
+		id, err := g.generate(tc.now)
+		if err != nil {
+			t.Fatalf("generate error: %v", err)
+		}
+		days, minute, _ := id.Components()
+		if minute != tc.minute {
+			t.Fatalf("minute field for %v: got %d want %d", tc.now, minute, tc.minute)
+		}
+		if got := days == utcDays+1; got != tc.nextDay {
+			t.Fatalf("day field for %v: got %d (UTC day %d)", tc.now, days, utcDays)
+		}
+		if got := g.Time(id); !got.Equal(tc.now) || got.Location() != kolkata {
+			t.Fatalf("Generator.Time: got %v want %v", got, tc.now.In(kolkata))
+		}
+	}
+}
+
+func TestWithLocationFallBack(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("LoadLocation error: %v", err)
+	}
+	g := NewGenerator(WithLocation(newYork))
+
+	// On 2024-11-03 the New York wall clock runs from 01:59 EDT back to
+	// 01:00 EST, so 05:00 through 05:59 UTC and 06:00 through 06:59 UTC share
+	// wall-clock minutes.
+	base := time.Date(2024, 11, 3, 5, 30, 0, 0, time.UTC)
+	cases := []struct {
+		now    time.Time
+		minute uint16
+	}{
+		{base, 1*60 + 30},
+		{base.Add(29 * time.Minute), 1*60 + 59},
+		{base.Add(30 * time.Minute), 1*60 + 59},
+		{base.Add(60 * time.Minute), 1*60 + 59},
+		{base.Add(90 * time.Minute), 2 * 60},
+	}
+	ids := make([]ID, len(cases))
+	for i, tc := range cases {
+		id, err := g.generate(tc.now)
+		if err != nil {
+			t.Fatalf("generate at %v error: %v", tc.now, err)
+		}
+		if _, minute, _ := id.Components(); minute != tc.minute {
+			t.Fatalf("minute field at %v: got %d want %d", tc.now, minute, tc.minute)
+		}
+		ids[i] = id
+	}
+	if !slices.IsSorted(ids) || HasDuplicates(ids) {
+		t.Fatalf("IDs across the fall-back are not strictly ascending: %v", ids)
+	}
+	if got, want := g.Time(ids[3]), base.Add(29*time.Minute); !got.Equal(want) {
+		t.Fatalf("Generator.Time in the repeated hour: got %v want %v", got, want)
+	}
+	if minute, _ := g.CurrentCounter(); !minute.Equal(base.Add(90 * time.Minute)) {
+		t.Fatalf("CurrentCounter minute: got %v want %v", minute, base.Add(90*time.Minute))
+	}
+}
+
+func TestGenerateUnique(t *testing.T) {
+	ids, err := GenerateUnique(nil, 5000, 8)
+	if err != nil {
+		t.Fatalf("GenerateUnique error: %v", err)
+	}
+	if len(ids) != 5000 {
+		t.Fatalf("GenerateUnique count: got %d want 5000", len(ids))
+	}
+
+	if _, err := GenerateUnique(NewGenerator(), 10, 0); err == nil {
+		t.Fatalf("expected error for zero goroutines")
+	}
+	// A shared entropy reader that always returns the same bytes collides.
+	g := NewGenerator(WithEntropy(bytes.NewReader(make([]byte, 64))))
+	if _, err := GenerateUnique(g, 4, 2); err == nil {
+		t.Fatalf("expected duplicate error")
+	}
+}
+
+func TestGenerateString(t *testing.T) {
+	s, err := GenerateString()
+	if err != nil {
+		t.Fatalf("GenerateString error: %v", err)
+	}
+	for _, encoded := range []string{s, MustGenerateString()} {
+		id, err := Parse(encoded)
+		if err != nil {
+			t.Fatalf("Parse(%q) error: %v", encoded, err)
+		}
+		if id.String() != encoded {
+			t.Fatalf("round trip: got %q want %q", id.String(), encoded)
+		}
+	}
+}
+
+func TestGeneratorMustGenerate(t *testing.T) {
+	now := time.Date(2024, 8, 18, 15, 30, 0, 0, time.UTC)
+	g := NewGenerator(WithNow(func() time.Time { return now }), WithRateLimit(1))
+
+	if id := g.MustGenerate(); !id.Time().Equal(now) {
+		t.Fatalf("MustGenerate time: got %v want %v", id.Time(), now)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected panic once the rate limit is reached")
+		}
+	}()
+	g.MustGenerate()
+}
+
+func TestWithRateLimit(t *testing.T) {
+	now := time.Date(2024, 8, 18, 15, 30, 0, 0, time.UTC)
+	g := NewGenerator(WithRateLimit(3))
+
+	for i := range 3 {
+		if _, err := g.generate(now); err != nil {
+			t.Fatalf("generate %d error: %v", i, err)
+		}
+	}
+	_, err := g.generate(now)
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("expected ErrRateLimited, got %v", err)
+	}
+	if errors.Is(err, ErrCounterOverflow) {
+		t.Fatalf("rate limit reported as counter overflow")
+	}
+
+	id, err := g.generate(now.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("generate in next minute error: %v", err)
+	}
+	if _, _, counter := id.Components(); counter != 0 {
+		t.Fatalf("next minute counter: got %d want 0", counter)
+	}
+}
+
+func TestWithCounterCeiling(t *testing.T) {
+	now := time.Date(2024, 8, 18, 15, 30, 0, 0, time.UTC)
+
+	g := NewGenerator(WithCounterCeiling(2))
+	for i := range 3 {
+		if _, err := g.generate(now); err != nil {
+			t.Fatalf("generate %d error: %v", i, err)
+		}
+	}
+	if _, err := g.generate(now); !errors.Is(err, ErrCounterOverflow) {
+		t.Fatalf("expected ErrCounterOverflow at the ceiling, got %v", err)
+	}
+
+	borrow := NewGenerator(WithCounterCeiling(2), WithBorrowFutureMinutes(1))
+	ids, err := borrow.generateMany(now, 3)
+	if err != nil {
+		t.Fatalf("generateMany error: %v", err)
+	}
+	id, err := borrow.generate(now)
+	if err != nil {
+		t.Fatalf("generate with borrowing error: %v", err)
+	}
+	if !id.Time().Equal(now.Add(time.Minute)) || id <= ids[len(ids)-1] {
+		t.Fatalf("borrowing did not engage at the ceiling: %s", id.Inspect())
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected panic for ceiling above the counter range")
+		}
+	}()
+	NewGenerator(WithCounterCeiling(counterMask + 1))
+}
+
+func TestWithNowAcrossDST(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("LoadLocation error: %v", err)
+	}
+
+	// Clocks jump from 01:59 EST to 03:00 EDT on 2024-03-10.
+	now := time.Date(2024, 3, 10, 1, 50, 0, 0, newYork)
+	g := NewGenerator(WithNow(func() time.Time { return now }))
+
+	var prev ID
+	for i := range 20 {
+		for j := range 2 {
+			id, err := g.Generate()
+			if err != nil {
+				t.Fatalf("Generate error at step %d: %v", i, err)
+			}
+			if id <= prev {
+				t.Fatalf("IDs not ascending at step %d: %s then %s", i, prev.Inspect(), id.Inspect())
+			}
+			if !id.Time().Equal(now) {
+				t.Fatalf("minute for %v: got %v", now, id.Time())
+			}
+			if j == 0 && i > 0 && id.Time().Sub(prev.Time()) != time.Minute {
+				t.Fatalf("minute gap at step %d: %v then %v", i, prev.Time(), id.Time())
+			}
+			prev = id
+		}
+		now = now.Add(time.Minute)
+	}
+	if got := now.In(newYork).Hour(); got != 3 {
+		t.Fatalf("test did not cross the transition, ended at %v", now.In(newYork))
+	}
+
+	// Under WithLocation the fields follow the New York wall clock: they skip
+	// the missing hour in March and hold at 01:59 EDT through the repeated
+	// hour in November, staying ascending throughout.
+	lastEDT := time.Date(2024, 11, 3, 1, 59, 0, 0, newYork)
+	for _, start := range []time.Time{
+		time.Date(2024, 3, 10, 6, 30, 0, 0, time.UTC),
+		time.Date(2024, 11, 3, 5, 30, 0, 0, time.UTC),
+	} {
+		now := start
+		g := NewGenerator(WithLocation(newYork), WithNow(func() time.Time { return now }))
+		var prev ID
+		for ; now.Before(start.Add(2 * time.Hour)); now = now.Add(time.Minute) {
+			for range 2 {
+				id, err := g.Generate()
+				if err != nil {
+					t.Fatalf("Generate error at %v: %v", now, err)
+				}
+				if id <= prev {
+					t.Fatalf("IDs not ascending at %v: %s then %s", now, prev.Inspect(), id.Inspect())
+				}
+				want := now
+				if now.After(lastEDT) && !now.After(lastEDT.Add(time.Hour)) {
+					want = lastEDT
+				}
+				if got := g.Time(id); !got.Equal(want) {
+					t.Fatalf("Generator.Time at %v: got %v want %v", now, got, want)
+				}
+				prev = id
+			}
+		}
+	}
+}
+
+func TestReset(t *testing.T) {
+	now := time.Date(2024, 8, 18, 15, 30, 0, 0, time.UTC)
+	g := NewGenerator(WithNow(func() time.Time { return now }))
+	if _, err := g.GenerateMany(5); err != nil {
+		t.Fatalf("GenerateMany error: %v", err)
+	}
+
+	g.Reset()
+	if st := g.State(); !st.Minute.IsZero() {
+		t.Fatalf("State after Reset: got %+v", st)
+	}
+	id, err := g.Generate()
+	if err != nil {
+		t.Fatalf("Generate error: %v", err)
+	}
+	if _, _, counter := id.Components(); counter != 0 || !id.Time().Equal(now) {
+		t.Fatalf("after Reset: got %s, want counter 0 in the same minute", id.Inspect())
+	}
+
+	prev := DefaultGenerator()
+	t.Cleanup(func() { SetDefaultGenerator(prev) })
+	SetDefaultGenerator(g)
+	ResetDefault()
+	if st := g.State(); !st.Minute.IsZero() {
+		t.Fatalf("State after ResetDefault: got %+v", st)
+	}
+}
+
+func TestWithRandomNodePrefix(t *testing.T) {
+	now := time.Date(2024, 8, 18, 15, 30, 0, 0, time.UTC)
+	const bits = 10
+
+	prefixes := make(map[uint16]bool)
+	for range 8 {
+		g := NewGenerator(WithRandomNodePrefix(bits))
+		a, err := g.generate(now)
+		if err != nil {
+			t.Fatalf("generate error: %v", err)
+		}
+		b, err := g.generate(now)
+		if err != nil {
+			t.Fatalf("generate error: %v", err)
+		}
+		if a.ReservedBits(bits) != b.ReservedBits(bits) {
+			t.Fatalf("prefix changed within a generator: %d then %d", a.ReservedBits(bits), b.ReservedBits(bits))
+		}
+		prefixes[a.ReservedBits(bits)] = true
+	}
+	// Eight draws of 10 bits all colliding is vanishingly unlikely.
+	if len(prefixes) < 2 {
+		t.Fatalf("every generator drew prefix %v", prefixes)
+	}
+}
+
+func TestCapacity(t *testing.T) {
+	cases := []struct {
+		name string
+		g    *Generator
+		want int
+	}{
+		{"default", NewGenerator(), 1 << counterBits},
+		{"reserved bits", NewGenerator(WithReservedBits(4, 3)), 1 << (counterBits - 4)},
+		{"node prefix", NewGenerator(WithRandomNodePrefix(6)), 1 << (counterBits - 6)},
+		{"ceiling", NewGenerator(WithReservedBits(4, 3), WithCounterCeiling(99)), 100},
+		{"rate limit", NewGenerator(WithCounterCeiling(99), WithRateLimit(10)), 10},
+		{"wide counter", NewGenerator(WithBitLayout(13, 11, 16)), 1 << 16},
+	}
+	now := time.Date(2024, 8, 18, 15, 30, 0, 0, time.UTC)
+	for _, tc := range cases {
+		if got := tc.g.Capacity(); got != tc.want {
+			t.Fatalf("%s: Capacity got %d want %d", tc.name, got, tc.want)
+		}
+		for i := range tc.want {
+			if _, err := tc.g.generate(now); err != nil {
+				t.Fatalf("%s: generate %d of %d failed: %v", tc.name, i+1, tc.want, err)
+			}
+		}
+		if _, err := tc.g.generate(now); err == nil {
+			t.Fatalf("%s: generated more than Capacity", tc.name)
+		}
+	}
+}
+
+func TestCurrentCounter(t *testing.T) {
+	now := time.Date(2024, 8, 18, 15, 30, 0, 0, time.UTC)
+	g := NewGenerator()
+	if minute, n := g.CurrentCounter(); !minute.IsZero() || n != 0 {
+		t.Fatalf("fresh generator: got %v, %d", minute, n)
+	}
+
+	if _, err := g.generateMany(now, 5); err != nil {
+		t.Fatalf("generateMany error: %v", err)
+	}
+	for range 2 {
+		if minute, n := g.CurrentCounter(); !minute.Equal(now) || n != 5 {
+			t.Fatalf("CurrentCounter: got %v, %d want %v, 5", minute, n, now)
+		}
+	}
+
+	id, err := g.generate(now)
+	if err != nil {
+		t.Fatalf("generate error: %v", err)
+	}
+	if _, _, counter := id.Components(); counter != 5 {
+		t.Fatalf("CurrentCounter advanced the counter: next counter %d", counter)
+	}
+	if _, err := g.generate(now.Add(time.Minute)); err != nil {
+		t.Fatalf("generate error: %v", err)
+	}
+	if minute, n := g.CurrentCounter(); !minute.Equal(now.Add(time.Minute)) || n != 1 {
+		t.Fatalf("CurrentCounter after a new minute: got %v, %d", minute, n)
+	}
+
+	wide := NewGenerator(WithBitLayout(13, 11, 16))
+	if _, err := wide.generateMany(now, wide.Capacity()); err != nil {
+		t.Fatalf("generateMany error: %v", err)
+	}
+	if _, n := wide.CurrentCounter(); n != wide.Capacity() {
+		t.Fatalf("CurrentCounter of a full 16-bit minute: got %d want %d", n, wide.Capacity())
+	}
+}
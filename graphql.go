@@ -0,0 +1,57 @@
+package miniulid
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// MarshalGQL writes the ID as a quoted Crockford string, implementing gqlgen's
+// graphql.Marshaler so ID can back a custom scalar. GraphQL clients expect ID
+// scalars as strings, so SetJSONFormat does not apply.
+func (id ID) MarshalGQL(w io.Writer) {
+	buf := make([]byte, 0, totalSize+2)
+	buf = append(buf, '"')
+	buf = append(buf, id.String()...)
+	buf = append(buf, '"')
+	w.Write(buf)
+}
+
+// UnmarshalGQL implements gqlgen's graphql.Unmarshaler. It accepts the
+// Crockford string and, since GraphQL ID inputs may also be integer literals,
+// the 40-bit value as an int, int64, or json.Number. The returned error names
+// the problem, such as a bad character or length, and gqlgen reports it to
+// the client as the input's validation error.
+func (id *ID) UnmarshalGQL(v any) error {
+	var parsed ID
+	var err error
+	switch v := v.(type) {
+	case string:
+		parsed, err = Parse(v)
+	case int:
+		parsed, err = fromGQLInt(int64(v))
+	case int64:
+		parsed, err = fromGQLInt(v)
+	case json.Number:
+		var n int64
+		if n, err = v.Int64(); err != nil {
+			return fmt.Errorf("miniulid: GraphQL number %s is not an integer", v)
+		}
+		parsed, err = fromGQLInt(n)
+	default:
+		return fmt.Errorf("miniulid: GraphQL value must be a string or integer, got %T", v)
+	}
+	if err != nil {
+		return err
+	}
+	*id = parsed
+	return nil
+}
+
+func fromGQLInt(v int64) (ID, error) {
+	id, err := FromInt64(v)
+	if err != nil {
+		return 0, err
+	}
+	return checkMinute(id)
+}
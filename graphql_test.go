@@ -0,0 +1,52 @@
+package miniulid
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestIDGraphQL(t *testing.T) {
+	id := ID(56755782866)
+
+	SetJSONFormat(JSONNumber)
+	var buf bytes.Buffer
+	id.MarshalGQL(&buf)
+	SetJSONFormat(JSONString)
+	if want := `"` + id.String() + `"`; buf.String() != want {
+		t.Fatalf("MarshalGQL: got %s want %s", buf.String(), want)
+	}
+
+	for _, v := range []any{id.String(), int(id), id.Int64(), json.Number("56755782866")} {
+		var got ID
+		if err := got.UnmarshalGQL(v); err != nil || got != id {
+			t.Fatalf("UnmarshalGQL(%#v): got %v, %v want %v", v, got, err, id)
+		}
+	}
+
+	invalidMinute := ID(uint64(minutesPerDay) << counterBits)
+	for _, tc := range []struct {
+		v    any
+		want error
+	}{
+		{"1MVEH16", errLength},
+		{"1MVEH16!", errInvalidChar},
+		{invalidMinute.Int64(), ErrInvalidMinute},
+		{json.Number("1.5"), nil},
+		{int64(-1), nil},
+		{true, nil},
+	} {
+		got := id
+		err := got.UnmarshalGQL(tc.v)
+		if err == nil {
+			t.Fatalf("UnmarshalGQL(%#v): expected error", tc.v)
+		}
+		if tc.want != nil && !errors.Is(err, tc.want) {
+			t.Fatalf("UnmarshalGQL(%#v): got %v want %v", tc.v, err, tc.want)
+		}
+		if got != id {
+			t.Fatalf("UnmarshalGQL(%#v) modified the ID on error", tc.v)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package miniulid
+
+import (
+	"fmt"
+	"time"
+)
+
+// ID48 is a 48-bit variant of ID with second precision and a wider counter,
+// encoded as 10 Crockford Base32 characters. Its fields, from the high bits:
+//
+//	days since 2020-01-01 (15) | minute of day (11) | second (6) | counter (16)
+//
+// ID48 and ID are distinct formats: their encoded forms differ in length and
+// their values are not comparable. Convert with ID.ToID48 and ID48.ToID.
+type ID48 uint64
+
+const (
+	// EncodedLen48 is the length of an encoded ID48.
+	EncodedLen48 = 10
+
+	secondBits    = 6
+	counter48Bits = 16
+	counter48Mask = 1<<counter48Bits - 1
+	maxValue48    = 1<<48 - 1
+)
+
+// NewID48 builds an ID48 from t's UTC day, minute, and second and counter.
+func NewID48(t time.Time, counter uint16) (ID48, error) {
+	days, minuteOfDay, err := splitTime(t)
+	if err != nil {
+		return 0, err
+	}
+	return ID48(uint64(days)<<(minutesBits+secondBits+counter48Bits) |
+		uint64(minuteOfDay)<<(secondBits+counter48Bits) |
+		uint64(t.UTC().Second())<<counter48Bits |
+		uint64(counter)), nil
+}
+
+// ParseID48 decodes the 10-character form of an ID48, rejecting values above
+// 48 bits, minute-of-day fields of 1440 or more with ErrInvalidMinute, and
+// second fields of 60 or more.
+func ParseID48(encoded string) (ID48, error) {
+	if len(encoded) != EncodedLen48 {
+		return 0, fmt.Errorf("miniulid: 48-bit encoded form must be %d characters", EncodedLen48)
+	}
+	value, err := decodeBase32(encoded, CurrentParseMode())
+	if err != nil {
+		return 0, err
+	}
+	if value > maxValue48 {
+		return 0, fmt.Errorf("miniulid: value exceeds 48 bits")
+	}
+
+	id := ID48(value)
+	_, minuteOfDay, second, _ := id.Components()
+	if minuteOfDay >= minutesPerDay {
+		return 0, fmt.Errorf("%w: %d", ErrInvalidMinute, minuteOfDay)
+	}
+	if second >= 60 {
+		return 0, fmt.Errorf("miniulid: second out of range: %d", second)
+	}
+	return id, nil
+}
+
+// String returns the 10-character Crockford Base32 form.
+func (id ID48) String() string {
+	var buf [EncodedLen48]byte
+	encodeBase32(buf[:], uint64(id))
+	return string(buf[:])
+}
+
+// Components returns the day, minute-of-day, second, and counter fields.
+func (id ID48) Components() (days, minuteOfDay uint16, second uint8, counter uint16) {
+	value := uint64(id)
+	counter = uint16(value & counter48Mask)
+	value >>= counter48Bits
+	second = uint8(value & (1<<secondBits - 1))
+	value >>= secondBits
+	minuteOfDay = uint16(value & minutesMask)
+	value >>= minutesBits
+	days = uint16(value & daysMask)
+	return
+}
+
+// Time returns the second-precision UTC time of the ID.
+func (id ID48) Time() time.Time {
+	days, minuteOfDay, second, _ := id.Components()
+	return time.Date(2020, time.January, 1+int(days), 0, int(minuteOfDay), int(second), 0, time.UTC)
+}
+
+// ToID48 widens id to an ID48 with second 0 and the same counter.
+func (id ID) ToID48() ID48 {
+	days, minuteOfDay, counter := id.Components()
+	return ID48(uint64(days)<<(minutesBits+secondBits+counter48Bits) |
+		uint64(minuteOfDay)<<(secondBits+counter48Bits) |
+		uint64(counter))
+}
+
+// ToID narrows id to an ID, dropping the second and the two high counter
+// bits. Distinct ID48s of the same minute may therefore map to the same ID.
+func (id ID48) ToID() ID {
+	days, minuteOfDay, _, counter := id.Components()
+	return ID(uint64(days)<<(minutesBits+counterBits) |
+		uint64(minuteOfDay)<<counterBits |
+		uint64(counter&counterMask))
+}
@@ -0,0 +1,69 @@
+package miniulid
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestID48RoundTrip(t *testing.T) {
+	ts := time.Date(2024, 8, 18, 15, 30, 42, 0, time.UTC)
+	for _, counter := range []uint16{0, 1234, counter48Mask} {
+		id, err := NewID48(ts, counter)
+		if err != nil {
+			t.Fatalf("NewID48 error: %v", err)
+		}
+		s := id.String()
+		if len(s) != EncodedLen48 {
+			t.Fatalf("String: got %q", s)
+		}
+		back, err := ParseID48(s)
+		if err != nil || back != id {
+			t.Fatalf("ParseID48(%q): got %v, %v want %v", s, back, err, id)
+		}
+		if !back.Time().Equal(ts) {
+			t.Fatalf("Time: got %v want %v", back.Time(), ts)
+		}
+		days, minuteOfDay, second, c := back.Components()
+		if days != 1691 || minuteOfDay != 930 || second != 42 || c != counter {
+			t.Fatalf("Components: got %d/%d/%d/%d", days, minuteOfDay, second, c)
+		}
+	}
+
+	if s := ID48(0).String(); s != "0000000000" {
+		t.Fatalf("zero ID48: got %q", s)
+	}
+	if _, err := ParseID48(ID48(maxValue48 + 1).String()); err == nil {
+		t.Fatalf("expected error beyond 48 bits")
+	}
+	if _, err := ParseID48(ID48(uint64(minutesPerDay) << (secondBits + counter48Bits)).String()); !errors.Is(err, ErrInvalidMinute) {
+		t.Fatalf("expected ErrInvalidMinute, got %v", err)
+	}
+	if _, err := ParseID48(ID48(60 << counter48Bits).String()); err == nil {
+		t.Fatalf("expected error for second 60")
+	}
+	if _, err := ParseID48("1MVEH16J"); err == nil {
+		t.Fatalf("expected length error for a 40-bit form")
+	}
+}
+
+func TestID48Conversions(t *testing.T) {
+	id := ID(56755782866)
+	wide := id.ToID48()
+	if wide.ToID() != id {
+		t.Fatalf("ToID48/ToID round trip: got %v want %v", wide.ToID(), id)
+	}
+	if !wide.Time().Equal(id.Time()) {
+		t.Fatalf("ToID48 time: got %v want %v", wide.Time(), id.Time())
+	}
+
+	ts := time.Date(2024, 8, 18, 15, 30, 42, 0, time.UTC)
+	narrowed, err := NewID48(ts, 1<<counterBits|7)
+	if err != nil {
+		t.Fatalf("NewID48 error: %v", err)
+	}
+	got := narrowed.ToID()
+	if !got.Time().Equal(ts.Truncate(time.Minute)) || got.CounterOrdinal() != 7 {
+		t.Fatalf("ToID: got %s", got.Inspect())
+	}
+}
@@ -0,0 +1,96 @@
+package miniulid
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+)
+
+// JSONFormat selects how ID.MarshalJSON encodes IDs.
+type JSONFormat uint32
+
+const (
+	// JSONString encodes IDs as their Crockford string, such as "1MVEH16J".
+	// It is the default.
+	JSONString JSONFormat = iota
+	// JSONNumber encodes IDs as their 40-bit integer value.
+	JSONNumber
+)
+
+var jsonFormat atomic.Uint32
+
+// SetJSONFormat sets the representation ID.MarshalJSON uses for the whole
+// process, and so that of NullID. Decoding accepts both representations
+// whatever the format. To pick the representation per struct field instead,
+// use NumericID.
+func SetJSONFormat(f JSONFormat) {
+	jsonFormat.Store(uint32(f))
+}
+
+// MarshalJSON encodes the ID as its Crockford string, or as a number when
+// SetJSONFormat selects JSONNumber.
+func (id ID) MarshalJSON() ([]byte, error) {
+	if JSONFormat(jsonFormat.Load()) == JSONNumber {
+		return NumericID(id).MarshalJSON()
+	}
+	buf := make([]byte, 0, totalSize+2)
+	buf = append(buf, '"')
+	buf = append(buf, id.String()...)
+	return append(buf, '"'), nil
+}
+
+// UnmarshalJSON decodes a JSON string holding the Crockford form or a JSON
+// number holding the 40-bit value, rejecting either if its minute-of-day
+// field is out of range. A JSON null leaves id unchanged.
+func (id *ID) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	var parsed ID
+	var err error
+	switch {
+	case len(data) >= 2 && data[0] == '"' && data[len(data)-1] == '"':
+		parsed, err = Parse(string(data[1 : len(data)-1]))
+	case len(data) > 0 && (data[0] == '-' || '0' <= data[0] && data[0] <= '9'):
+		var n NumericID
+		if err = n.UnmarshalJSON(data); err == nil {
+			parsed, err = checkMinute(ID(n))
+		}
+	default:
+		return fmt.Errorf("miniulid: JSON value must be a string or number, got %s", data)
+	}
+	if err != nil {
+		return err
+	}
+	*id = parsed
+	return nil
+}
+
+// NumericID is an ID that encodes to JSON as its 40-bit integer value instead
+// of the Crockford string. Convert with NumericID(id) and ID(n) to choose the
+// representation per struct field.
+type NumericID ID
+
+// MarshalJSON encodes the ID as a bare JSON number.
+func (n NumericID) MarshalJSON() ([]byte, error) {
+	return strconv.AppendInt(nil, ID(n).Int64(), 10), nil
+}
+
+// UnmarshalJSON decodes a JSON number within the 40-bit range. A JSON null
+// leaves n unchanged.
+func (n *NumericID) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	v, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil {
+		return err
+	}
+	id, err := FromInt64(v)
+	if err != nil {
+		return err
+	}
+	*n = NumericID(id)
+	return nil
+}
@@ -0,0 +1,138 @@
+package miniulid
+
+import (
+	"encoding"
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestNumericIDJSON(t *testing.T) {
+	id, err := GenerateWithComponents(time.Date(2024, 8, 18, 15, 30, 0, 0, time.UTC), 1234)
+	if err != nil {
+		t.Fatalf("GenerateWithComponents error: %v", err)
+	}
+
+	type payload struct {
+		ID NumericID `json:"id"`
+	}
+	data, err := json.Marshal(payload{ID: NumericID(id)})
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	if want := `{"id":` + strconv.FormatInt(id.Int64(), 10) + `}`; string(data) != want {
+		t.Fatalf("Marshal: got %s want %s", data, want)
+	}
+
+	var back payload
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if ID(back.ID) != id {
+		t.Fatalf("round trip: got %v want %v", ID(back.ID), id)
+	}
+
+	for _, input := range []string{`{"id":-1}`, `{"id":1099511627776}`, `{"id":"0F5VD3YH"}`} {
+		if err := json.Unmarshal([]byte(input), &back); err == nil {
+			t.Fatalf("expected error for %s", input)
+		}
+	}
+}
+
+func TestIDJSON(t *testing.T) {
+	id := ID(56755782866)
+	data, err := json.Marshal(id)
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	if want := `"1MVEH16J"`; string(data) != want {
+		t.Fatalf("Marshal: got %s want %s", data, want)
+	}
+
+	var back ID
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if back != id {
+		t.Fatalf("round trip: got %v want %v", back, id)
+	}
+	back = 0
+	if err := json.Unmarshal([]byte(`56755782866`), &back); err != nil || back != id {
+		t.Fatalf("numeric input: got %v, %v", back, err)
+	}
+}
+
+func TestTextMarshaler(t *testing.T) {
+	var (
+		_ encoding.TextMarshaler   = ID(0)
+		_ encoding.TextUnmarshaler = (*ID)(nil)
+	)
+
+	id := ID(56755782866)
+	text, err := id.MarshalText()
+	if err != nil || string(text) != "1MVEH16J" {
+		t.Fatalf("MarshalText: got %q, %v", text, err)
+	}
+	var back ID
+	if err := back.UnmarshalText([]byte("1mveh16j")); err != nil || back != id {
+		t.Fatalf("UnmarshalText: got %v, %v", back, err)
+	}
+	if err := back.UnmarshalText([]byte("1MVEH16U")); err == nil {
+		t.Fatalf("expected UnmarshalText error")
+	}
+
+	counts := map[ID]int{id: 3}
+	data, err := json.Marshal(counts)
+	if err != nil {
+		t.Fatalf("json.Marshal map error: %v", err)
+	}
+	if string(data) != `{"1MVEH16J":3}` {
+		t.Fatalf("json.Marshal map: got %s", data)
+	}
+	var decoded map[ID]int
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded[id] != 3 {
+		t.Fatalf("json.Unmarshal map: got %v, %v", decoded, err)
+	}
+}
+
+func TestJSONFormat(t *testing.T) {
+	t.Cleanup(func() { SetJSONFormat(JSONString) })
+	type event struct {
+		ID   ID     `json:"id"`
+		Null NullID `json:"null"`
+	}
+	id := ID(56755782866)
+	in := event{ID: id, Null: NullID{ID: id, Valid: true}}
+
+	for _, tc := range []struct {
+		format JSONFormat
+		want   string
+	}{
+		{JSONString, `{"id":"1MVEH16J","null":"1MVEH16J"}`},
+		{JSONNumber, `{"id":56755782866,"null":56755782866}`},
+	} {
+		SetJSONFormat(tc.format)
+		data, err := json.Marshal(in)
+		if err != nil {
+			t.Fatalf("json.Marshal error: %v", err)
+		}
+		if string(data) != tc.want {
+			t.Fatalf("format %d: got %s want %s", tc.format, data, tc.want)
+		}
+		// Decoding accepts both forms whatever the format.
+		for _, input := range []string{`{"id":"1MVEH16J","null":56755782866}`, `{"id":56755782866,"null":"1MVEH16J"}`} {
+			var out event
+			if err := json.Unmarshal([]byte(input), &out); err != nil || out != in {
+				t.Fatalf("json.Unmarshal(%s): got %+v, %v", input, out, err)
+			}
+		}
+	}
+
+	var bad ID
+	for _, input := range []string{`true`, `1.5`, `-1`, `1099511627776`, strconv.Itoa(1440 << counterBits)} {
+		if err := json.Unmarshal([]byte(input), &bad); err == nil {
+			t.Fatalf("json.Unmarshal(%s): expected error", input)
+		}
+	}
+}
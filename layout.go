@@ -0,0 +1,83 @@
+package miniulid
+
+import "time"
+
+// Layout describes how the day, minute, and counter fields are arranged in an
+// ID. The encoded form is the same for every layout, so String works
+// unchanged; parse and decode fields with the Layout the ID was generated with.
+type Layout uint8
+
+const (
+	// LayoutTimeFirst places the day and minute in the high bits. IDs sort
+	// chronologically. This is the default and the layout assumed by the ID
+	// methods.
+	LayoutTimeFirst Layout = iota
+	// LayoutEntropyFirst places the counter in the high bits, spreading
+	// consecutive IDs across the key space to avoid write hot spots in
+	// range-sharded stores. IDs no longer sort by time, either numerically
+	// or lexically.
+	LayoutEntropyFirst
+)
+
+const timeBits = daysBits + minutesBits
+
+// Pack converts a LayoutTimeFirst ID into layout l.
+func (l Layout) Pack(id ID) ID {
+	return l.pack(id, counterBits)
+}
+
+// Unpack converts an ID in layout l into LayoutTimeFirst.
+func (l Layout) Unpack(id ID) ID {
+	return l.unpack(id, counterBits)
+}
+
+// pack is Pack for a counter field of width bits.
+func (l Layout) pack(id ID, width uint8) ID {
+	if l != LayoutEntropyFirst {
+		return id
+	}
+	return (id&(1<<width-1))<<(Bits-width) | id>>width
+}
+
+// unpack is Unpack for a counter field of width bits.
+func (l Layout) unpack(id ID, width uint8) ID {
+	if l != LayoutEntropyFirst {
+		return id
+	}
+	return (id&(1<<(Bits-width)-1))<<width | id>>(Bits-width)
+}
+
+// Parse decodes an ID in layout l, rejecting values whose minute-of-day field
+// is out of range with ErrInvalidMinute.
+func (l Layout) Parse(encoded string) (ID, error) {
+	id, err := decode(encoded)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := checkMinute(l.Unpack(id)); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// Components returns the day, minute, and counter fields of an ID in layout l.
+func (l Layout) Components(id ID) (days uint16, minuteOfDay uint16, counter uint16) {
+	return l.Unpack(id).Components()
+}
+
+// Time returns the minute-precision UTC time of an ID in layout l.
+func (l Layout) Time(id ID) time.Time {
+	return l.Unpack(id).Time()
+}
+
+// String returns the layout name.
+func (l Layout) String() string {
+	switch l {
+	case LayoutTimeFirst:
+		return "time-first"
+	case LayoutEntropyFirst:
+		return "entropy-first"
+	default:
+		return "unknown"
+	}
+}
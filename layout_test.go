@@ -0,0 +1,48 @@
+package miniulid
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLayoutRoundTrip(t *testing.T) {
+	now := time.Date(2024, 8, 18, 15, 30, 0, 0, time.UTC)
+	for _, layout := range []Layout{LayoutTimeFirst, LayoutEntropyFirst} {
+		g := NewGenerator(WithLayout(layout))
+		if _, err := g.generate(now); err != nil {
+			t.Fatalf("%v: generate error: %v", layout, err)
+		}
+		id, err := g.generate(now)
+		if err != nil {
+			t.Fatalf("%v: generate error: %v", layout, err)
+		}
+
+		parsed, err := layout.Parse(id.String())
+		if err != nil {
+			t.Fatalf("%v: Parse error: %v", layout, err)
+		}
+		if parsed != id {
+			t.Fatalf("%v: Parse mismatch: got %v want %v", layout, parsed, id)
+		}
+		if got := layout.Time(parsed); !got.Equal(now) {
+			t.Fatalf("%v: Time: got %v want %v", layout, got, now)
+		}
+		if _, minutes, counter := layout.Components(parsed); minutes != 930 || counter != 1 {
+			t.Fatalf("%v: Components: minute=%d counter=%d", layout, minutes, counter)
+		}
+		if layout.Pack(layout.Unpack(id)) != id {
+			t.Fatalf("%v: Pack/Unpack not inverse", layout)
+		}
+	}
+
+	entropyFirst := LayoutEntropyFirst.Pack(ID(minutesPerDay) << counterBits)
+	if _, err := LayoutEntropyFirst.Parse(entropyFirst.String()); !errors.Is(err, ErrInvalidMinute) {
+		t.Fatalf("expected ErrInvalidMinute, got %v", err)
+	}
+
+	timeFirst, _ := GenerateWithComponents(now, 1)
+	if entropyFirst := LayoutEntropyFirst.Pack(timeFirst); entropyFirst>>timeBits != 1 {
+		t.Fatalf("entropy-first high bits: got %d want 1", entropyFirst>>timeBits)
+	}
+}
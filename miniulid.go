@@ -1,14 +1,44 @@
+// Package miniulid implements a compact 40-bit, minute-precision identifier
+// encoded as 8 Crockford Base32 characters.
+//
+// IDs are totally ordered: for two IDs sharing the same epoch, numeric order
+// (and therefore the order of their encoded strings) is the order of their
+// minutes, then of their counters. Equal IDs have identical minutes and
+// counters.
+//
+// Every time.Time accepted by the package is converted to UTC before its day
+// and minute are extracted, so the same instant always yields the same fields
+// regardless of its location. Daylight saving transitions therefore never
+// skip or repeat minutes. The exception is a Generator configured with
+// WithLocation, whose fields follow that location's wall clock.
 package miniulid
 
 import (
+	"bytes"
+	"cmp"
+	"crypto/subtle"
+	"errors"
 	"fmt"
-	"sync"
+	"io"
+	"iter"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 )
 
 // ID represents the compact 40-bit identifier.
 type ID uint64
 
+const (
+	// Bits is the number of significant bits in an ID.
+	Bits = daysBits + minutesBits + counterBits
+	// EncodedLen is the length of the Crockford Base32 encoded form.
+	EncodedLen = 8
+	// Alphabet is the Crockford Base32 alphabet used for encoding.
+	Alphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+)
+
 const (
 	daysBits    = 15
 	minutesBits = 11
@@ -18,11 +48,25 @@ const (
 	minutesMask = (1 << minutesBits) - 1
 	daysMask    = (1 << daysBits) - 1
 
-	totalBits = daysBits + minutesBits + counterBits
-	totalSize = 8
+	totalBits = Bits
+	totalSize = EncodedLen
+
+	maxValue = (1 << totalBits) - 1
+
+	minutesPerDay = 24 * 60
+
+	hexSize = totalBits / 4
 )
 
-const encodeAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+const encodeAlphabet = Alphabet
+
+// checkAlphabet holds the 37 Crockford check symbols.
+const checkAlphabet = Alphabet + "*~$=U"
+
+const asciiSpace = " \t\n\v\f\r"
+
+// parseSkew is the clock skew tolerated by ParseValidated.
+const parseSkew = 5 * time.Minute
 
 var (
 	epoch          = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
@@ -30,9 +74,16 @@ var (
 	errTimeFuture  = fmt.Errorf("miniulid: time beyond supported range")
 	errInvalidChar = fmt.Errorf("miniulid: invalid Crockford character")
 	errLength      = fmt.Errorf("miniulid: encoded form must be %d characters", totalSize)
+	errOverflow    = fmt.Errorf("miniulid: value exceeds %d bits", totalBits)
+	errUnderflow   = fmt.Errorf("miniulid: value below zero")
 )
 
-var defaultMinuteCounter = &minuteCounter{}
+var (
+	// ErrInvalidMinute is returned when a decoded minute-of-day field is not in 0-1439.
+	ErrInvalidMinute = errors.New("miniulid: minute of day out of range")
+	// ErrChecksum is returned by ParseWithCheck when the check symbol does not match.
+	ErrChecksum = errors.New("miniulid: check symbol mismatch")
+)
 
 var decodeAlphabet = map[byte]uint8{
 	'0': 0, '1': 1, '2': 2, '3': 3, '4': 4,
@@ -50,25 +101,34 @@ var decodeAlphabet = map[byte]uint8{
 }
 
 // Generate produces a new ID using the current UTC minute and a monotonic counter.
+// The zero ID is reserved and never generated; see Generator.Generate.
 func Generate() (ID, error) {
-	now := time.Now().UTC()
-	counter, err := defaultMinuteCounter.next(now)
-	if err != nil {
-		return 0, err
-	}
-	return GenerateWithComponents(now, counter)
+	return DefaultGenerator().Generate()
 }
 
 // MustGenerate is a convenience helper that panics on error.
 func MustGenerate() ID {
+	return DefaultGenerator().MustGenerate()
+}
+
+// GenerateString produces a new ID from the default generator and returns its
+// encoded form.
+func GenerateString() (string, error) {
 	id, err := Generate()
 	if err != nil {
-		panic(err)
+		return "", err
 	}
-	return id
+	return id.String(), nil
+}
+
+// MustGenerateString is like GenerateString but panics on error.
+func MustGenerateString() string {
+	return MustGenerate().String()
 }
 
 // GenerateWithComponents builds an ID from a timestamp and a user-supplied counter value.
+// The timestamp's zone is irrelevant: it is converted to UTC before the day and
+// minute are extracted.
 func GenerateWithComponents(t time.Time, counter uint16) (ID, error) {
 	if counter > counterMask {
 		return 0, fmt.Errorf("miniulid: counter value overflow (max %d)", counterMask)
@@ -79,30 +139,395 @@ func GenerateWithComponents(t time.Time, counter uint16) (ID, error) {
 		return 0, err
 	}
 
-	value := (uint64(dayCount) << (minutesBits + counterBits)) |
+	return FromComponents(dayCount, minuteOfDay, counter)
+}
+
+// GenerateWithTime builds an ID from a timestamp and two bytes read from
+// entropy, of which the lower 14 bits form the counter segment.
+func GenerateWithTime(t time.Time, entropy io.Reader) (ID, error) {
+	counter, err := readCounter(entropy)
+	if err != nil {
+		return 0, err
+	}
+	return GenerateWithComponents(t, counter&counterMask)
+}
+
+// readCounter reads two entropy bytes as a 16-bit value; callers mask it to
+// their counter width.
+func readCounter(entropy io.Reader) (uint16, error) {
+	var buf [2]byte
+	if _, err := io.ReadFull(entropy, buf[:]); err != nil {
+		return 0, fmt.Errorf("miniulid: reading entropy: %w", err)
+	}
+	return uint16(buf[0])<<8 | uint16(buf[1]), nil
+}
+
+// FromComponents packs raw day, minute-of-day, and counter fields into an ID.
+// It is the inverse of Components.
+func FromComponents(days, minuteOfDay, counter uint16) (ID, error) {
+	if days > daysMask {
+		return 0, fmt.Errorf("miniulid: day value overflow (max %d)", daysMask)
+	}
+	if minuteOfDay >= minutesPerDay {
+		return 0, fmt.Errorf("miniulid: minute of day out of range (max %d)", minutesPerDay-1)
+	}
+	if counter > counterMask {
+		return 0, fmt.Errorf("miniulid: counter value overflow (max %d)", counterMask)
+	}
+
+	value := (uint64(days) << (minutesBits + counterBits)) |
 		(uint64(minuteOfDay) << counterBits) |
 		uint64(counter)
 
 	return ID(value), nil
 }
 
-// Parse decodes an encoded string into an ID.
+// ExampleID deterministically maps n to a valid, representative ID for
+// documentation and test fixtures. Consecutive inputs land 37 minutes apart
+// from 2024-01-01 UTC with scattered counters, and distinct n below 2^20 give
+// distinct IDs; larger n wrap around.
+func ExampleID(n int) ID {
+	const (
+		startDay = 1461 // 2024-01-01
+		count    = 1 << 20
+		stride   = 37
+	)
+
+	step := uint64(n) % count
+	minute := startDay*minutesPerDay + step*stride
+	counter := (step * 2731) & counterMask
+
+	return ID(minute/minutesPerDay<<(minutesBits+counterBits) |
+		minute%minutesPerDay<<counterBits |
+		counter)
+}
+
+// GenerateSequence returns n ascending, unique IDs starting at counter 0 of
+// start's minute and moving to the next minute each time a minute's 16384
+// counter values are used up. At the epoch minute it starts at counter 1,
+// since ID(0) is never generated. It depends on neither the clock nor randomness,
+// so the same arguments always give the same IDs, as benchmarks and golden
+// tests need.
+func GenerateSequence(start time.Time, n int) ([]ID, error) {
+	return NewGenerator().generateMany(start, n)
+}
+
+// Parse decodes an encoded string into an ID. It rejects values whose
+// minute-of-day field is 1440 or more with ErrInvalidMinute. Lowercase and the
+// ambiguous I, L, and O are accepted unless SetParseMode selects
+// ParseStrictMode. Parse assumes LayoutTimeFirst; use Layout.Parse for other
+// layouts.
 func Parse(encoded string) (ID, error) {
+	return parseIn(encoded, CurrentParseMode())
+}
+
+// parseIn is Parse with an explicit parse mode.
+func parseIn(encoded string, mode ParseMode) (ID, error) {
+	id, err := decodeIn(encoded, mode)
+	if err != nil {
+		return 0, err
+	}
+	return checkMinute(id)
+}
+
+// ParseLenient decodes s like Parse after trimming surrounding ASCII
+// whitespace and then one matching pair of single or double quotes. It
+// accepts lowercase and the ambiguous I, L, and O whatever the parse mode.
+func ParseLenient(s string) (ID, error) {
+	s = strings.Trim(s, asciiSpace)
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		s = s[1 : len(s)-1]
+	}
+	return parseIn(s, ParseLenientMode)
+}
+
+// ParseMany decodes the sep-separated IDs in data. Empty tokens at the end,
+// such as after a final newline, are ignored. On failure the error names the
+// zero-based token index and the offending token.
+func ParseMany(data []byte, sep byte) ([]ID, error) {
+	tokens := bytes.Split(data, []byte{sep})
+	for len(tokens) > 0 && len(tokens[len(tokens)-1]) == 0 {
+		tokens = tokens[:len(tokens)-1]
+	}
+
+	ids := make([]ID, len(tokens))
+	for i, token := range tokens {
+		id, err := Parse(string(token))
+		if err != nil {
+			return nil, fmt.Errorf("miniulid: token %d %q: %w", i, token, err)
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
+// ParseComponents decodes encoded like Parse and returns its day,
+// minute-of-day, and counter fields.
+func ParseComponents(encoded string) (days, minuteOfDay, counter uint16, err error) {
+	id, err := Parse(encoded)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	days, minuteOfDay, counter = id.Components()
+	return days, minuteOfDay, counter, nil
+}
+
+// decode converts the Crockford form to its 40-bit value without checking the
+// fields, in the current parse mode.
+func decode(encoded string) (ID, error) {
+	return decodeIn(encoded, CurrentParseMode())
+}
+
+func decodeIn(encoded string, mode ParseMode) (ID, error) {
 	if len(encoded) != totalSize {
 		return 0, errLength
 	}
+	value, err := decodeBase32(encoded, mode)
+	return ID(value), err
+}
 
+// decodeBase32 converts Crockford characters to the value they encode, five
+// bits per character. It is shared by every encoded width.
+func decodeBase32(encoded string, mode ParseMode) (uint64, error) {
+	strict := mode == ParseStrictMode
 	var value uint64
-	for _, r := range encoded {
-		c := byte(r)
+	for i := 0; i < len(encoded); i++ {
+		c := encoded[i]
 		v, ok := decodeAlphabet[c]
-		if !ok {
+		if !ok || strict && encodeAlphabet[v] != c {
 			return 0, fmt.Errorf("%w: %q", errInvalidChar, c)
 		}
 		value = (value << 5) | uint64(v)
 	}
+	return value, nil
+}
 
-	return ID(value), nil
+func checkMinute(id ID) (ID, error) {
+	if !id.InRange() {
+		_, minuteOfDay, _ := id.Components()
+		return 0, fmt.Errorf("%w: %d", ErrInvalidMinute, minuteOfDay)
+	}
+	return id, nil
+}
+
+// Normalize maps encoded to its canonical form: letters are uppercased and the
+// ambiguous I, L, and O are replaced by 1, 1, and 0. It does not check the length.
+func Normalize(encoded string) (string, error) {
+	buf := []byte(encoded)
+	for i, c := range buf {
+		v, ok := decodeAlphabet[c]
+		if !ok {
+			return "", fmt.Errorf("%w: %q", errInvalidChar, c)
+		}
+		buf[i] = encodeAlphabet[v]
+	}
+	return string(buf), nil
+}
+
+// IsCanonical reports whether encoded parses and is exactly the form String
+// produces: uppercase, with no I, L, or O substitutes.
+func IsCanonical(encoded string) bool {
+	id, err := Parse(encoded)
+	return err == nil && id.String() == encoded
+}
+
+// Pattern returns a regular expression matching the characters of Alphabet,
+// for use as a JSON Schema or OpenAPI pattern so that gateways reject
+// malformed IDs. It is derived from the alphabet, so the excluded I, L, O,
+// and U never match even though Parse reads the first three as 1, 1, and 0.
+// In ParseLenientMode it is "^[0-9A-HJKMNP-TV-Za-hjkmnp-tv-z]{8}$", accepting
+// lowercase as Parse does; in ParseStrictMode it matches uppercase only. A
+// match can still fail Parse's minute-of-day check.
+func Pattern() string {
+	strict := CurrentParseMode() == ParseStrictMode
+	var accepted [256]bool
+	for i := 0; i < len(encodeAlphabet); i++ {
+		c := encodeAlphabet[i]
+		accepted[c] = true
+		if !strict && 'A' <= c && c <= 'Z' {
+			accepted[c|0x20] = true
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("^[")
+	for c := 0; c < len(accepted); c++ {
+		if !accepted[c] {
+			continue
+		}
+		end := c
+		for end+1 < len(accepted) && accepted[end+1] {
+			end++
+		}
+		b.WriteByte(byte(c))
+		if end > c {
+			if end > c+1 {
+				b.WriteByte('-')
+			}
+			b.WriteByte(byte(end))
+		}
+		c = end
+	}
+	fmt.Fprintf(&b, "]{%d}$", totalSize)
+	return b.String()
+}
+
+// ParseFuzzy decodes encoded like Parse in ParseLenientMode, whatever the
+// parse mode, and also reports every ambiguous character it substituted, as
+// "o→0" style entries in input order, so callers can warn about transcription
+// errors. Plain case changes are not reported.
+func ParseFuzzy(encoded string) (ID, []string, error) {
+	id, err := parseIn(encoded, ParseLenientMode)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var fixes []string
+	for i := 0; i < len(encoded); i++ {
+		if c := encoded[i]; isSubstitute(c) {
+			fixes = append(fixes, fmt.Sprintf("%c→%c", c, encodeAlphabet[decodeAlphabet[c]]))
+		}
+	}
+	return id, fixes, nil
+}
+
+// Report describes the ambiguity substitutions ParseReport applied.
+type Report struct {
+	// Positions holds the byte offsets, ascending, of the I, L, and O
+	// characters, in either case, that were read as 1, 1, and 0.
+	Positions []int
+}
+
+// Substituted reports whether any ambiguous character was substituted, in
+// which case differently spelled inputs may decode to the same ID.
+func (r Report) Substituted() bool {
+	return len(r.Positions) > 0
+}
+
+// ParseReport decodes encoded like Parse in ParseLenientMode, whatever the
+// parse mode, and reports where it substituted ambiguous characters, so
+// imports can flag input that may silently deduplicate against another
+// spelling of the same ID. Plain case changes are not reported.
+func ParseReport(encoded string) (ID, Report, error) {
+	id, err := parseIn(encoded, ParseLenientMode)
+	if err != nil {
+		return 0, Report{}, err
+	}
+
+	var r Report
+	for i := 0; i < len(encoded); i++ {
+		if isSubstitute(encoded[i]) {
+			r.Positions = append(r.Positions, i)
+		}
+	}
+	return id, r, nil
+}
+
+// isSubstitute reports whether c is accepted only as a substitute for a
+// different alphabet character, not as that character in either case.
+func isSubstitute(c byte) bool {
+	canonical := encodeAlphabet[decodeAlphabet[c]]
+	return canonical != c && canonical != c&^0x20
+}
+
+// StringWithCheck returns the encoded form followed by a Crockford check
+// symbol, the ID's value modulo 37.
+func (id ID) StringWithCheck() string {
+	var buf [totalSize + 1]byte
+	id.encode((*[totalSize]byte)(buf[:totalSize]))
+	buf[totalSize] = checkAlphabet[uint64(id)%uint64(len(checkAlphabet))]
+	return string(buf[:])
+}
+
+// ParseWithCheck decodes a string produced by StringWithCheck, returning
+// ErrChecksum when the check symbol does not match the decoded value. Like
+// Parse, it accepts a lowercase or ambiguous check symbol unless SetParseMode
+// selects ParseStrictMode.
+func ParseWithCheck(encoded string) (ID, error) {
+	if len(encoded) != totalSize+1 {
+		return 0, fmt.Errorf("miniulid: checked form must be %d characters", totalSize+1)
+	}
+	id, err := Parse(encoded[:totalSize])
+	if err != nil {
+		return 0, err
+	}
+
+	check := encoded[totalSize]
+	want := checkAlphabet[uint64(id)%uint64(len(checkAlphabet))]
+	if CurrentParseMode() != ParseStrictMode {
+		if check == 'u' {
+			check = 'U'
+		}
+		if v, ok := decodeAlphabet[check]; ok {
+			check = encodeAlphabet[v]
+		}
+	}
+	if check != want {
+		return 0, fmt.Errorf("%w: got %q want %q", ErrChecksum, encoded[totalSize], want)
+	}
+	return id, nil
+}
+
+// ParseLegacy decodes the dashed form emitted by older tooling, such as
+// "01ABZ9QT-0052": an encoded ID of which only the day and minute are used,
+// followed by the counter in decimal. Parse does not accept this form.
+func ParseLegacy(s string) (ID, error) {
+	prefix, digits, ok := strings.Cut(s, "-")
+	if !ok {
+		return 0, fmt.Errorf("miniulid: legacy form %q lacks a counter separator", s)
+	}
+	minute, err := Parse(prefix)
+	if err != nil {
+		return 0, err
+	}
+	counter, err := strconv.ParseUint(digits, 10, 16)
+	if err != nil || counter > counterMask {
+		return 0, fmt.Errorf("miniulid: legacy counter %q must be a decimal number up to %d", digits, counterMask)
+	}
+	return minute.TruncateMinute() | ID(counter), nil
+}
+
+// ParseValidated decodes encoded like Parse and additionally rejects IDs whose
+// time is more than a few minutes after now. Decoded IDs never precede the
+// epoch, so only the future bound needs checking.
+func ParseValidated(encoded string, now time.Time) (ID, error) {
+	id, err := Parse(encoded)
+	if err != nil {
+		return 0, err
+	}
+	if limit := now.Add(parseSkew); id.Time().After(limit) {
+		return 0, fmt.Errorf("%w: %s is after %s", errTimeFuture, id.Time().Format(time.RFC3339), limit.UTC().Format(time.RFC3339))
+	}
+	return id, nil
+}
+
+// ReadID reads exactly one encoded ID from r. It returns io.EOF when r is
+// exhausted and io.ErrUnexpectedEOF when fewer than 8 bytes remain.
+func ReadID(r io.Reader) (ID, error) {
+	var buf [totalSize]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return 0, err
+	}
+	return Parse(string(buf[:]))
+}
+
+// HexString returns the 40-bit value as 10 lowercase, zero-padded hex digits.
+// Lexical order of the result matches numeric order.
+func (id ID) HexString() string {
+	return fmt.Sprintf("%0*x", hexSize, uint64(id))
+}
+
+// ParseHex decodes the 10-digit hex form produced by HexString. Like Parse,
+// it rejects values whose minute-of-day field is out of range.
+func ParseHex(encoded string) (ID, error) {
+	if len(encoded) != hexSize {
+		return 0, fmt.Errorf("miniulid: hex form must be %d characters", hexSize)
+	}
+	v, err := strconv.ParseUint(encoded, 16, totalBits)
+	if err != nil {
+		return 0, fmt.Errorf("miniulid: invalid hex form %q", encoded)
+	}
+	return checkMinute(ID(v))
 }
 
 // FromInt64 converts a 40-bit integer representation into an ID.
@@ -111,27 +536,138 @@ func FromInt64(v int64) (ID, error) {
 		return 0, fmt.Errorf("miniulid: negative value")
 	}
 	if v>>totalBits != 0 {
-		return 0, fmt.Errorf("miniulid: value exceeds %d bits", totalBits)
+		return 0, errOverflow
+	}
+	return ID(v), nil
+}
+
+// FromProto converts a protobuf fixed64 field value into an ID.
+func FromProto(v uint64) (ID, error) {
+	if v>>totalBits != 0 {
+		return 0, errOverflow
 	}
 	return ID(v), nil
 }
 
+// ToProto returns the value for a protobuf fixed64 field.
+func (id ID) ToProto() uint64 {
+	return uint64(id)
+}
+
 // Int64 returns the 40-bit integer representation.
 func (id ID) Int64() int64 {
 	return int64(id)
 }
 
-// String returns the Crockford Base32 encoded form.
+// String returns the Crockford Base32 encoded form. Its only allocation is
+// the returned string, as the encoding buffer stays on the stack, so there is
+// nothing to pool; AppendText and WriteTo avoid even that, and Strings and
+// JoinStrings share one allocation across a batch.
 func (id ID) String() string {
 	var buf [totalSize]byte
-	value := uint64(id)
+	id.encode(&buf)
+	return string(buf[:])
+}
+
+// AppendText appends the Crockford Base32 encoded form to b. It implements
+// encoding.TextAppender.
+func (id ID) AppendText(b []byte) ([]byte, error) {
+	var buf [totalSize]byte
+	id.encode(&buf)
+	return append(b, buf[:]...), nil
+}
+
+// MarshalText returns the Crockford Base32 encoded form. It implements
+// encoding.TextMarshaler, so IDs also work as JSON object keys and in any
+// text-based codec.
+func (id ID) MarshalText() ([]byte, error) {
+	return id.AppendText(make([]byte, 0, totalSize))
+}
+
+// UnmarshalText decodes the encoded form like Parse. It implements
+// encoding.TextUnmarshaler.
+func (id *ID) UnmarshalText(text []byte) error {
+	parsed, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*id = parsed
+	return nil
+}
+
+// Strings returns the encoded forms of ids. The strings share one backing
+// buffer, so the whole slice costs two allocations instead of one per ID.
+func Strings(ids []ID) []string {
+	joined := JoinStrings(ids, "")
+	out := make([]string, len(ids))
+	for i := range out {
+		out[i] = joined[i*totalSize : (i+1)*totalSize]
+	}
+	return out
+}
+
+// JoinStrings returns the encoded forms of ids separated by sep, built in a
+// single allocation.
+func JoinStrings(ids []ID, sep string) string {
+	if len(ids) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.Grow(len(ids)*(totalSize+len(sep)) - len(sep))
+	var buf [totalSize]byte
+	for i, id := range ids {
+		if i > 0 {
+			b.WriteString(sep)
+		}
+		id.encode(&buf)
+		b.Write(buf[:])
+	}
+	return b.String()
+}
 
+// WriteTo writes the Crockford Base32 encoded form to w. It implements io.WriterTo.
+func (id ID) WriteTo(w io.Writer) (int64, error) {
+	var buf [totalSize]byte
+	id.encode(&buf)
+	n, err := w.Write(buf[:])
+	return int64(n), err
+}
+
+// Quintets returns the eight 5-bit groups of the ID, most significant first,
+// in the order String encodes them.
+func (id ID) Quintets() [totalSize]uint8 {
+	var q [totalSize]uint8
+	value := uint64(id)
 	for i := totalSize - 1; i >= 0; i-- {
-		buf[i] = encodeAlphabet[int(value&31)]
+		q[i] = uint8(value & 31)
 		value >>= 5
 	}
+	return q
+}
 
-	return string(buf[:])
+// encode writes the 8 Crockford characters of id into buf. The loop over the
+// 5-bit groups is unrolled: each character takes the group at a fixed shift,
+// most significant first, which avoids the loop-carried shift of value.
+func (id ID) encode(buf *[totalSize]byte) {
+	value := uint64(id)
+
+	buf[0] = encodeAlphabet[(value>>35)&31]
+	buf[1] = encodeAlphabet[(value>>30)&31]
+	buf[2] = encodeAlphabet[(value>>25)&31]
+	buf[3] = encodeAlphabet[(value>>20)&31]
+	buf[4] = encodeAlphabet[(value>>15)&31]
+	buf[5] = encodeAlphabet[(value>>10)&31]
+	buf[6] = encodeAlphabet[(value>>5)&31]
+	buf[7] = encodeAlphabet[value&31]
+}
+
+// encodeBase32 fills buf with the low 5*len(buf) bits of value as Crockford
+// characters. encode is its unrolled form for the 8-character ID.
+func encodeBase32(buf []byte, value uint64) {
+	for i := len(buf) - 1; i >= 0; i-- {
+		buf[i] = encodeAlphabet[value&31]
+		value >>= 5
+	}
 }
 
 // Time reconstructs the original minute-precision UTC time.
@@ -165,44 +701,274 @@ func (id ID) Components() (days uint16, minuteOfDay uint16, counter uint16) {
 	return
 }
 
-func splitTime(t time.Time) (uint16, uint16, error) {
-	utc := t.UTC()
-	if utc.Before(epoch) {
-		return 0, 0, errTimePast
+// DayBucket returns the day field, the number of days since the epoch.
+func (id ID) DayBucket() uint16 {
+	return uint16((uint64(id) >> (minutesBits + counterBits)) & daysMask)
+}
+
+// HourBucket returns the UTC hour of day, 0-23, derived from the minute field.
+func (id ID) HourBucket() int {
+	return int((uint64(id)>>counterBits)&minutesMask) / 60
+}
+
+// InRange reports whether the ID fits in 40 bits and its minute-of-day field
+// names a real minute. The 11-bit field can hold up to 2047, but only 0-1439
+// are produced by generation; larger values indicate corrupt input.
+func (id ID) InRange() bool {
+	if id > maxValue {
+		return false
 	}
+	_, minuteOfDay, _ := id.Components()
+	return minuteOfDay < minutesPerDay
+}
 
-	duration := utc.Sub(epoch)
-	days := duration / (24 * time.Hour)
-	if days >= 1<<daysBits {
-		return 0, 0, errTimeFuture
+// Validate checks every invariant of an ID, for example one decoded from
+// untrusted binary input: that it fits in 40 bits, that its minute-of-day
+// field is below 1440 (ErrInvalidMinute), and that its time lies within
+// [MinTime, MaxTime]. It returns the first violation found, wrapped with the
+// offending ID.
+func (id ID) Validate() error {
+	if id > maxValue {
+		return fmt.Errorf("miniulid: invalid ID %d: %w", uint64(id), errOverflow)
+	}
+	if _, minuteOfDay, _ := id.Components(); minuteOfDay >= minutesPerDay {
+		return fmt.Errorf("miniulid: invalid ID %s: %w: %d", id, ErrInvalidMinute, minuteOfDay)
+	}
+	switch t := id.Time(); {
+	case t.Before(MinTime()):
+		return fmt.Errorf("miniulid: invalid ID %s: %w", id, errTimePast)
+	case t.After(MaxTime()):
+		return fmt.Errorf("miniulid: invalid ID %s: %w", id, errTimeFuture)
 	}
+	return nil
+}
 
-	minuteOfDay := utc.Hour()*60 + utc.Minute()
-	return uint16(days), uint16(minuteOfDay), nil
+// ReservedBits returns the high n bits of the counter field, the tag set by
+// WithReservedBits. n is capped at the counter width.
+func (id ID) ReservedBits(n uint8) uint16 {
+	n = min(n, counterBits)
+	_, _, counter := id.Components()
+	return counter >> (counterBits - n)
 }
 
-type minuteCounter struct {
-	mu     sync.Mutex
-	minute time.Time
-	value  uint16
+// Inspect returns a one-line diagnostic description of the ID, such as
+// miniulid{str:1MVEH16J int:56755782866 time:2024-08-18T15:30Z days:1691 min:930 ctr:1234}.
+func (id ID) Inspect() string {
+	days, minuteOfDay, counter := id.Components()
+	return fmt.Sprintf("miniulid{str:%s int:%d time:%s days:%d min:%d ctr:%d}",
+		id, id.Int64(), id.Time().Format("2006-01-02T15:04Z07:00"), days, minuteOfDay, counter)
 }
 
-func (mc *minuteCounter) next(t time.Time) (uint16, error) {
-	mc.mu.Lock()
-	defer mc.mu.Unlock()
+// Equal reports whether id and other are the same ID.
+func (id ID) Equal(other ID) bool {
+	return id == other
+}
 
-	currentMinute := t.UTC().Truncate(time.Minute)
+// Compare returns -1, 0, or +1 depending on whether id sorts before, equal to,
+// or after other.
+func (id ID) Compare(other ID) int {
+	return Cmp(id, other)
+}
+
+// EqualConstantTime reports whether id and other are the same ID in time that
+// does not depend on where they differ, for comparing IDs used as secrets.
+func (id ID) EqualConstantTime(other ID) bool {
+	var a, b [binarySize]byte
+	id.putBytes(a[:])
+	other.putBytes(b[:])
+	high := subtle.ConstantTimeEq(int32(id>>totalBits), int32(other>>totalBits))
+	return subtle.ConstantTimeCompare(a[:], b[:])&high == 1
+}
+
+// Cmp compares a and b like cmp.Compare. It can be passed directly to
+// slices.SortFunc and similar functions.
+func Cmp(a, b ID) int {
+	return cmp.Compare(a, b)
+}
+
+// Min returns the smallest ID in ids. Like slices.Min, it panics if ids is empty.
+func Min(ids []ID) ID {
+	return slices.Min(ids)
+}
+
+// Max returns the largest ID in ids. Like slices.Max, it panics if ids is empty.
+func Max(ids []ID) ID {
+	return slices.Max(ids)
+}
+
+// TruncateMinute returns the smallest ID of the same minute by clearing the counter.
+func (id ID) TruncateMinute() ID {
+	return id &^ counterMask
+}
+
+// TruncateDay returns the smallest ID of the same day by clearing the minute and counter.
+func (id ID) TruncateDay() ID {
+	return id &^ (minutesMask<<counterBits | counterMask)
+}
+
+// CounterOrdinal returns the counter field: under the default sequential
+// generator, the zero-based position of the ID within its minute.
+func (id ID) CounterOrdinal() uint16 {
+	return uint16(id & counterMask)
+}
+
+// MinuteFill returns the counter as a fraction of the highest counter value,
+// from 0 for the first ID of a minute to 1 for the last, to spot minutes close
+// to overflowing.
+func (id ID) MinuteFill() float64 {
+	return float64(id.CounterOrdinal()) / counterMask
+}
 
-	if mc.minute.IsZero() || !mc.minute.Equal(currentMinute) {
-		mc.minute = currentMinute
-		mc.value = 0
-		return 0, nil
+// MatchesTime reports whether t falls in the minute of id, for example to
+// check in an ORM hook that a stored creation time agrees with the ID.
+func (id ID) MatchesTime(t time.Time) bool {
+	return id.Time().Equal(t.UTC().Truncate(time.Minute))
+}
+
+// WithCounter returns id with its counter field replaced by c, keeping the day
+// and minute. It fails if c exceeds the 14-bit counter range.
+func (id ID) WithCounter(c uint16) (ID, error) {
+	if c > counterMask {
+		return 0, fmt.Errorf("miniulid: counter value overflow (max %d)", counterMask)
+	}
+	return id.TruncateMinute() | ID(c), nil
+}
+
+// SameMinute reports whether id and other share the day and minute fields,
+// whatever their counters. Both must use LayoutTimeFirst.
+func (id ID) SameMinute(other ID) bool {
+	return (id^other)>>counterBits == 0
+}
+
+// Next returns the numerically following ID. It does not skip unused
+// minute-of-day values, so the result is only meaningful as a range bound.
+func (id ID) Next() (ID, error) {
+	if id >= maxValue {
+		return 0, fmt.Errorf("%w: no ID after %s", errOverflow, id)
 	}
+	return id + 1, nil
+}
+
+// Prev returns the numerically preceding ID. Like Next, it is intended for
+// building exclusive range bounds.
+func (id ID) Prev() (ID, error) {
+	if id == 0 {
+		return 0, fmt.Errorf("%w: no ID before %s", errUnderflow, id)
+	}
+	return id - 1, nil
+}
 
-	if mc.value == counterMask {
-		return 0, fmt.Errorf("miniulid: counter overflow for minute %s", currentMinute.Format(time.RFC3339))
+// HasDuplicates reports whether any ID appears more than once in ids.
+func HasDuplicates(ids []ID) bool {
+	seen := make(map[ID]struct{}, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			return true
+		}
+		seen[id] = struct{}{}
 	}
+	return false
+}
 
-	mc.value++
-	return mc.value, nil
+// Duplicates returns each ID that appears more than once in ids, in the order
+// of its first repetition.
+func Duplicates(ids []ID) []ID {
+	counts := make(map[ID]int, len(ids))
+	var dups []ID
+	for _, id := range ids {
+		counts[id]++
+		if counts[id] == 2 {
+			dups = append(dups, id)
+		}
+	}
+	return dups
+}
+
+// CounterDistribution counts the IDs in ids using each counter value. A
+// healthy entropy source spreads counters evenly; a few values with large
+// counts point to a reader returning the same bytes.
+func CounterDistribution(ids []ID) map[uint16]int {
+	dist := make(map[uint16]int)
+	for _, id := range ids {
+		_, _, counter := id.Components()
+		dist[counter]++
+	}
+	return dist
+}
+
+// MinuteSpread counts the IDs in ids falling in each minute of the day,
+// whatever their day.
+func MinuteSpread(ids []ID) map[uint16]int {
+	spread := make(map[uint16]int)
+	for _, id := range ids {
+		_, minuteOfDay, _ := id.Components()
+		spread[minuteOfDay]++
+	}
+	return spread
+}
+
+// CursorAt returns the smallest ID of the minute containing t, for paginating
+// by creation time: "WHERE id >= CursorAt(t)" selects every ID from that
+// minute onward, and "WHERE id < CursorAt(t)" everything before it.
+func CursorAt(t time.Time) (ID, error) {
+	return GenerateWithComponents(t, 0)
+}
+
+// Range yields every ID from the first minute of start through the last counter
+// value of the minute of end, in ascending order. Minutes outside the supported
+// range are skipped. Each minute holds 16384 IDs, so callers should keep the
+// window small.
+func Range(start, end time.Time) iter.Seq[ID] {
+	return func(yield func(ID) bool) {
+		t := start.UTC().Truncate(time.Minute)
+		if t.Before(epoch) {
+			t = epoch
+		}
+
+		for ; !t.After(end); t = t.Add(time.Minute) {
+			base, err := GenerateWithComponents(t, 0)
+			if err != nil {
+				return
+			}
+			for counter := ID(0); counter <= counterMask; counter++ {
+				if !yield(base | counter) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// MinTime returns the earliest representable minute, the epoch 2020-01-01 UTC.
+func MinTime() time.Time {
+	return epoch
+}
+
+// MaxTime returns the latest representable minute, 23:59 UTC on the last of
+// the 2^15 days after the epoch (2109-09-18).
+func MaxTime() time.Time {
+	return epoch.AddDate(0, 0, daysMask).Add((minutesPerDay - 1) * time.Minute)
+}
+
+// Representable reports whether an ID can be generated for t, that is whether
+// t falls within the minutes from MinTime through MaxTime.
+func Representable(t time.Time) bool {
+	_, _, err := splitTime(t)
+	return err == nil
+}
+
+// splitTime returns the UTC day count since the epoch and minute of day of t.
+func splitTime(t time.Time) (uint16, uint16, error) {
+	utc := t.UTC()
+	year, month, day := utc.Date()
+	days := time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Sub(epoch) / (24 * time.Hour)
+	if days < 0 {
+		return 0, 0, errTimePast
+	}
+	if days >= 1<<daysBits {
+		return 0, 0, errTimeFuture
+	}
+
+	minuteOfDay := utc.Hour()*60 + utc.Minute()
+	return uint16(days), uint16(minuteOfDay), nil
 }
@@ -1,9 +1,17 @@
 package miniulid
 
 import (
+	"bytes"
 	"errors"
+	"io"
+	"maps"
+	"math/rand/v2"
+	"regexp"
+	"slices"
+	"strings"
 	"testing"
 	"time"
+	_ "time/tzdata" // zone data for the time zone tests
 )
 
 func TestGenerateWithComponents(t *testing.T) {
@@ -73,7 +81,1155 @@ func TestParseErrors(t *testing.T) {
 	if _, err := Parse("!!!!!!!!"); err == nil || !errors.Is(err, errInvalidChar) {
 		t.Fatalf("expected errInvalidChar, got %v", err)
 	}
+	// İ is two bytes whose rune truncates to '0'.
+	if _, err := Parse("İ000000"); !errors.Is(err, errInvalidChar) {
+		t.Fatalf("expected errInvalidChar for a multibyte character, got %v", err)
+	}
 	if _, err := FromInt64(1<<totalBits | 1); err == nil {
 		t.Fatalf("expected overflow error")
 	}
 }
+
+func TestRange(t *testing.T) {
+	start := time.Date(2024, 8, 18, 23, 59, 30, 0, time.UTC)
+	end := start.Add(time.Minute)
+
+	var (
+		count int
+		prev  ID
+	)
+	for id := range Range(start, end) {
+		if count > 0 && id <= prev {
+			t.Fatalf("Range not ascending: %v after %v", id, prev)
+		}
+		prev = id
+		count++
+	}
+	if got, want := count, 2*(counterMask+1); got != want {
+		t.Fatalf("Range count: got %d want %d", got, want)
+	}
+	if days, minutes, counter := prev.Components(); minutes != 0 || counter != counterMask || days == 0 {
+		t.Fatalf("unexpected last ID components: %d %d %d", days, minutes, counter)
+	}
+
+	count = 0
+	for range Range(epoch.Add(-time.Hour), epoch) {
+		count++
+		if count == 10 {
+			break
+		}
+	}
+	if count != 10 {
+		t.Fatalf("Range did not stop on break: %d", count)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	id, err := GenerateWithComponents(time.Date(2024, 8, 18, 15, 30, 0, 0, time.UTC), 1234)
+	if err != nil {
+		t.Fatalf("GenerateWithComponents error: %v", err)
+	}
+
+	minute := id.TruncateMinute()
+	if !minute.Time().Equal(id.Time()) {
+		t.Fatalf("TruncateMinute time: got %v want %v", minute.Time(), id.Time())
+	}
+	if _, _, counter := minute.Components(); counter != 0 {
+		t.Fatalf("TruncateMinute counter: got %d want 0", counter)
+	}
+
+	day := id.TruncateDay()
+	if want := time.Date(2024, 8, 18, 0, 0, 0, 0, time.UTC); !day.Time().Equal(want) {
+		t.Fatalf("TruncateDay time: got %v want %v", day.Time(), want)
+	}
+	if _, minutes, counter := day.Components(); minutes != 0 || counter != 0 {
+		t.Fatalf("TruncateDay components: minute=%d counter=%d", minutes, counter)
+	}
+}
+
+func TestMinuteFill(t *testing.T) {
+	base := time.Date(2024, 8, 18, 15, 30, 0, 0, time.UTC)
+	cases := []struct {
+		counter uint16
+		fill    float64
+	}{
+		{0, 0},
+		{counterMask / 2, float64(counterMask/2) / counterMask},
+		{counterMask, 1},
+	}
+	for _, tc := range cases {
+		id, err := GenerateWithComponents(base, tc.counter)
+		if err != nil {
+			t.Fatalf("GenerateWithComponents error: %v", err)
+		}
+		if got := id.CounterOrdinal(); got != tc.counter {
+			t.Fatalf("CounterOrdinal: got %d want %d", got, tc.counter)
+		}
+		if got := id.MinuteFill(); got != tc.fill {
+			t.Fatalf("MinuteFill at %d: got %v want %v", tc.counter, got, tc.fill)
+		}
+	}
+}
+
+func TestMatchesTime(t *testing.T) {
+	created := time.Date(2024, 8, 18, 15, 30, 42, 0, time.UTC)
+	id, err := GenerateWithComponents(created, 1234)
+	if err != nil {
+		t.Fatalf("GenerateWithComponents error: %v", err)
+	}
+
+	for _, match := range []time.Time{created, created.Truncate(time.Minute), created.Add(17 * time.Second), created.In(time.FixedZone("UTC+2", 2*3600))} {
+		if !id.MatchesTime(match) {
+			t.Fatalf("MatchesTime(%v) = false for %s", match, id.Inspect())
+		}
+	}
+	for _, other := range []time.Time{created.Add(time.Minute), created.Add(-time.Minute), created.AddDate(0, 0, 1)} {
+		if id.MatchesTime(other) {
+			t.Fatalf("MatchesTime(%v) = true for %s", other, id.Inspect())
+		}
+	}
+}
+
+func TestWithCounter(t *testing.T) {
+	id := ID(56755782866)
+	for _, c := range []uint16{0, 1, counterMask} {
+		got, err := id.WithCounter(c)
+		if err != nil {
+			t.Fatalf("WithCounter(%d) error: %v", c, err)
+		}
+		days, minute, counter := got.Components()
+		wantDays, wantMinute, _ := id.Components()
+		if days != wantDays || minute != wantMinute || counter != c {
+			t.Fatalf("WithCounter(%d): got %s", c, got.Inspect())
+		}
+	}
+	if _, err := id.WithCounter(counterMask + 1); err == nil {
+		t.Fatalf("expected error for counter above the range")
+	}
+}
+
+func TestSameMinute(t *testing.T) {
+	now := time.Date(2024, 8, 18, 15, 30, 0, 0, time.UTC)
+	a, _ := GenerateWithComponents(now, 1)
+	b, _ := GenerateWithComponents(now, counterMask)
+	c, _ := GenerateWithComponents(now.Add(time.Minute), 1)
+
+	if !a.SameMinute(b) || !b.SameMinute(a) {
+		t.Fatalf("%s and %s should share a minute", a.Inspect(), b.Inspect())
+	}
+	if a.SameMinute(c) || b.SameMinute(c) {
+		t.Fatalf("%s and %s should be in different minutes", a.Inspect(), c.Inspect())
+	}
+}
+
+func TestNextPrev(t *testing.T) {
+	id := ID(41)
+	if next, err := id.Next(); err != nil || next != 42 {
+		t.Fatalf("Next: got %v, %v", next, err)
+	}
+	if prev, err := id.Prev(); err != nil || prev != 40 {
+		t.Fatalf("Prev: got %v, %v", prev, err)
+	}
+	if _, err := ID(maxValue).Next(); !errors.Is(err, errOverflow) {
+		t.Fatalf("expected errOverflow, got %v", err)
+	}
+	if _, err := ID(0).Prev(); !errors.Is(err, errUnderflow) {
+		t.Fatalf("expected errUnderflow, got %v", err)
+	}
+}
+
+func TestDuplicates(t *testing.T) {
+	unique := []ID{1, 2, 3}
+	if HasDuplicates(unique) {
+		t.Fatalf("HasDuplicates reported duplicates in %v", unique)
+	}
+	if dups := Duplicates(unique); len(dups) != 0 {
+		t.Fatalf("Duplicates: got %v want none", dups)
+	}
+
+	ids := []ID{5, 1, 5, 2, 1, 5}
+	if !HasDuplicates(ids) {
+		t.Fatalf("HasDuplicates missed duplicates in %v", ids)
+	}
+	dups := Duplicates(ids)
+	if len(dups) != 2 || dups[0] != 5 || dups[1] != 1 {
+		t.Fatalf("Duplicates: got %v want [5 1]", dups)
+	}
+}
+
+func TestDistribution(t *testing.T) {
+	start := time.Date(2024, 8, 18, 15, 30, 0, 0, time.UTC)
+	var ids []ID
+	var err error
+	for m := range 3 {
+		for c := range uint16(4) {
+			id, err := GenerateWithComponents(start.Add(time.Duration(m)*time.Minute), c%2)
+			if err != nil {
+				t.Fatalf("GenerateWithComponents error: %v", err)
+			}
+			ids = append(ids, id)
+		}
+	}
+
+	if got, want := CounterDistribution(ids), map[uint16]int{0: 6, 1: 6}; !maps.Equal(got, want) {
+		t.Fatalf("CounterDistribution: got %v want %v", got, want)
+	}
+	if got, want := MinuteSpread(ids), map[uint16]int{930: 4, 931: 4, 932: 4}; !maps.Equal(got, want) {
+		t.Fatalf("MinuteSpread: got %v want %v", got, want)
+	}
+
+	g := NewGenerator(WithEntropy(bytes.NewReader(bytes.Repeat([]byte{0x12, 0x34}, 100))))
+	stuck := make([]ID, 100)
+	for i := range stuck {
+		if stuck[i], err = g.generate(start); err != nil {
+			t.Fatalf("generate error: %v", err)
+		}
+	}
+	if dist := CounterDistribution(stuck); len(dist) != 1 {
+		t.Fatalf("repeating entropy should use one counter value, got %v", dist)
+	}
+}
+
+func TestWriteTo(t *testing.T) {
+	var buf bytes.Buffer
+	ids := []ID{0, 1234567890, maxValue}
+	for _, id := range ids {
+		n, err := id.WriteTo(&buf)
+		if err != nil {
+			t.Fatalf("WriteTo error: %v", err)
+		}
+		if n != totalSize {
+			t.Fatalf("WriteTo count: got %d want %d", n, totalSize)
+		}
+	}
+	if got, want := buf.String(), "00000000"+ID(1234567890).String()+"ZZZZZZZZ"; got != want {
+		t.Fatalf("WriteTo output: got %q want %q", got, want)
+	}
+}
+
+func TestReadID(t *testing.T) {
+	last, _ := FromComponents(daysMask, minutesPerDay-1, counterMask)
+	ids := []ID{7, 56755782866, last}
+	var buf bytes.Buffer
+	for _, id := range ids {
+		buf.WriteString(id.String())
+	}
+
+	r := bytes.NewReader(buf.Bytes())
+	for i, want := range ids {
+		got, err := ReadID(r)
+		if err != nil {
+			t.Fatalf("ReadID %d error: %v", i, err)
+		}
+		if got != want {
+			t.Fatalf("ReadID %d: got %v want %v", i, got, want)
+		}
+	}
+	if _, err := ReadID(r); err != io.EOF {
+		t.Fatalf("expected io.EOF, got %v", err)
+	}
+
+	if _, err := ReadID(bytes.NewReader([]byte("0F5V"))); err != io.ErrUnexpectedEOF {
+		t.Fatalf("expected io.ErrUnexpectedEOF, got %v", err)
+	}
+}
+
+func TestParseValidated(t *testing.T) {
+	now := time.Date(2024, 8, 18, 15, 30, 0, 0, time.UTC)
+	id, _ := GenerateWithComponents(now.Add(time.Minute), 1)
+	if got, err := ParseValidated(id.String(), now); err != nil || got != id {
+		t.Fatalf("ParseValidated: got %v, %v", got, err)
+	}
+
+	future, _ := GenerateWithComponents(now.AddDate(1, 0, 0), 1)
+	if _, err := ParseValidated(future.String(), now); !errors.Is(err, errTimeFuture) {
+		t.Fatalf("expected errTimeFuture, got %v", err)
+	}
+	last, _ := FromComponents(daysMask, minutesPerDay-1, counterMask)
+	if _, err := ParseValidated(last.String(), now); !errors.Is(err, errTimeFuture) {
+		t.Fatalf("expected errTimeFuture, got %v", err)
+	}
+	if _, err := ParseValidated("ABC", now); !errors.Is(err, errLength) {
+		t.Fatalf("expected errLength, got %v", err)
+	}
+}
+
+func TestOrdering(t *testing.T) {
+	base := time.Date(2024, 8, 18, 15, 30, 0, 0, time.UTC)
+	sameMinuteLow, _ := GenerateWithComponents(base, 1)
+	sameMinuteHigh, _ := GenerateWithComponents(base, counterMask)
+	nextMinute, _ := GenerateWithComponents(base.Add(time.Minute), 0)
+	nextDay, _ := GenerateWithComponents(base.AddDate(0, 0, 1).Add(-time.Hour), 0)
+
+	ids := []ID{nextDay, sameMinuteHigh, nextMinute, sameMinuteLow}
+	slices.SortFunc(ids, ID.Compare)
+	want := []ID{sameMinuteLow, sameMinuteHigh, nextMinute, nextDay}
+	for i := range want {
+		if !ids[i].Equal(want[i]) {
+			t.Fatalf("position %d: got %v want %v", i, ids[i], want[i])
+		}
+		if i > 0 && ids[i-1].String() >= ids[i].String() {
+			t.Fatalf("encoded order mismatch at %d", i)
+		}
+	}
+
+	if sameMinuteLow.Compare(sameMinuteLow) != 0 || sameMinuteLow.Compare(nextMinute) != -1 || nextDay.Compare(nextMinute) != 1 {
+		t.Fatalf("Compare results inconsistent")
+	}
+	if sameMinuteLow.Equal(sameMinuteHigh) {
+		t.Fatalf("Equal reported distinct IDs as equal")
+	}
+}
+
+// TestEncodedOrderProperty checks that sorting encoded strings
+// lexicographically orders IDs numerically, the property that range queries
+// on string columns rely on.
+func TestEncodedOrderProperty(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	var ids []ID
+	for range 20000 {
+		id, err := FromComponents(uint16(rng.IntN(daysMask+1)), uint16(rng.IntN(minutesPerDay)), uint16(rng.IntN(counterMask+1)))
+		if err != nil {
+			t.Fatalf("FromComponents error: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	// Every digit value at every character position, with the other digits
+	// at their extremes, covers the carries between 5-bit groups.
+	for pos := range totalSize {
+		shift := 5 * (totalSize - 1 - pos)
+		for digit := range ID(32) {
+			ids = append(ids, digit<<shift, digit<<shift|(maxValue&^(31<<shift)))
+		}
+	}
+
+	encoded := Strings(ids)
+	slices.Sort(encoded)
+	slices.Sort(ids)
+	for i, id := range ids {
+		if encoded[i] != id.String() {
+			t.Fatalf("position %d: lexical order has %q, numeric order %q", i, encoded[i], id.String())
+		}
+		if id.InRange() {
+			if back, err := Parse(encoded[i]); err != nil || back != id {
+				t.Fatalf("Parse(%q): got %v, %v want %v", encoded[i], back, err, id)
+			}
+		}
+	}
+}
+
+func TestPublicConstants(t *testing.T) {
+	if Bits != 40 || EncodedLen != 8 || len(Alphabet) != 32 {
+		t.Fatalf("unexpected constants: Bits=%d EncodedLen=%d len(Alphabet)=%d", Bits, EncodedLen, len(Alphabet))
+	}
+	if got := len(ID(maxValue).String()); got != EncodedLen {
+		t.Fatalf("encoded length: got %d want %d", got, EncodedLen)
+	}
+	for i := 0; i < len(Alphabet); i++ {
+		if v, ok := decodeAlphabet[Alphabet[i]]; !ok || int(v) != i {
+			t.Fatalf("Alphabet[%d]=%q decodes to %d", i, Alphabet[i], v)
+		}
+	}
+}
+
+func TestGenerateWithTime(t *testing.T) {
+	ts := time.Date(2024, 8, 18, 15, 30, 0, 0, time.UTC)
+	id, err := GenerateWithTime(ts, bytes.NewReader([]byte{0xD2, 0x34}))
+	if err != nil {
+		t.Fatalf("GenerateWithTime error: %v", err)
+	}
+	if _, _, counter := id.Components(); counter != 0x1234 {
+		t.Fatalf("counter: got %#x want %#x", counter, 0x1234)
+	}
+	if _, err := GenerateWithTime(ts, bytes.NewReader([]byte{1})); err == nil {
+		t.Fatalf("expected error for short entropy")
+	}
+}
+
+func TestProto(t *testing.T) {
+	id := ID(1234567890)
+	back, err := FromProto(id.ToProto())
+	if err != nil {
+		t.Fatalf("FromProto error: %v", err)
+	}
+	if back != id {
+		t.Fatalf("FromProto mismatch: got %v want %v", back, id)
+	}
+	if _, err := FromProto(1 << totalBits); !errors.Is(err, errOverflow) {
+		t.Fatalf("expected errOverflow, got %v", err)
+	}
+	if _, err := FromProto(^uint64(0)); !errors.Is(err, errOverflow) {
+		t.Fatalf("expected errOverflow, got %v", err)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	cases := map[string]string{
+		"0f5vd3yh": "0F5VD3YH",
+		"OOOOOOOO": "00000000",
+		"oooooooo": "00000000",
+		"IiLl1111": "11111111",
+		"ZZZZZZZZ": "ZZZZZZZZ",
+	}
+	for input, want := range cases {
+		got, err := Normalize(input)
+		if err != nil {
+			t.Fatalf("Normalize(%q) error: %v", input, err)
+		}
+		if got != want {
+			t.Fatalf("Normalize(%q): got %q want %q", input, got, want)
+		}
+	}
+	if _, err := Normalize("0F5VD3YU"); !errors.Is(err, errInvalidChar) {
+		t.Fatalf("expected errInvalidChar, got %v", err)
+	}
+
+	id, fixes, err := ParseFuzzy("000001vO")
+	if err != nil {
+		t.Fatalf("ParseFuzzy error: %v", err)
+	}
+	if want, _ := Parse("000001V0"); id != want {
+		t.Fatalf("ParseFuzzy: got %v want %v", id, want)
+	}
+	if len(fixes) != 1 || fixes[0] != "O→0" {
+		t.Fatalf("ParseFuzzy fixes: got %q", fixes)
+	}
+}
+
+func TestParseReport(t *testing.T) {
+	for _, c := range "IiLlOo" {
+		input := "000001V" + string(c)
+		id, report, err := ParseReport(input)
+		if err != nil {
+			t.Fatalf("ParseReport(%q) error: %v", input, err)
+		}
+		want, _ := Parse(input)
+		if id != want {
+			t.Fatalf("ParseReport(%q): got %v want %v", input, id, want)
+		}
+		if !report.Substituted() || !slices.Equal(report.Positions, []int{7}) {
+			t.Fatalf("ParseReport(%q) positions: got %v want [7]", input, report.Positions)
+		}
+	}
+
+	_, report, err := ParseReport("0o1Il00v")
+	if err != nil {
+		t.Fatalf("ParseReport error: %v", err)
+	}
+	if !slices.Equal(report.Positions, []int{1, 3, 4}) {
+		t.Fatalf("ParseReport positions: got %v want [1 3 4]", report.Positions)
+	}
+
+	_, report, err = ParseReport("1mveh16j")
+	if err != nil || report.Substituted() {
+		t.Fatalf("ParseReport of a lowercase ID: got %+v, %v", report, err)
+	}
+	if _, _, err := ParseReport("1MVEH16U"); !errors.Is(err, errInvalidChar) {
+		t.Fatalf("expected errInvalidChar, got %v", err)
+	}
+}
+
+func TestFromComponents(t *testing.T) {
+	id, err := GenerateWithComponents(time.Date(2024, 8, 18, 15, 30, 0, 0, time.UTC), 1234)
+	if err != nil {
+		t.Fatalf("GenerateWithComponents error: %v", err)
+	}
+	back, err := FromComponents(id.Components())
+	if err != nil {
+		t.Fatalf("FromComponents error: %v", err)
+	}
+	if back != id {
+		t.Fatalf("FromComponents mismatch: got %v want %v", back, id)
+	}
+
+	if _, err := FromComponents(daysMask+1, 0, 0); err == nil {
+		t.Fatalf("expected day overflow error")
+	}
+	if _, err := FromComponents(0, minutesPerDay, 0); err == nil {
+		t.Fatalf("expected minute range error")
+	}
+	if _, err := FromComponents(0, 0, counterMask+1); err == nil {
+		t.Fatalf("expected counter overflow error")
+	}
+}
+
+func TestInspect(t *testing.T) {
+	id, err := GenerateWithComponents(time.Date(2024, 7, 30, 8, 0, 0, 0, time.UTC), 52)
+	if err != nil {
+		t.Fatalf("GenerateWithComponents error: %v", err)
+	}
+	got := id.Inspect()
+	for _, want := range []string{"str:" + id.String(), "time:2024-07-30T08:00Z", "min:480", "ctr:52"} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("Inspect() = %q, missing %q", got, want)
+		}
+	}
+}
+
+func TestInRange(t *testing.T) {
+	valid, _ := FromComponents(daysMask, minutesPerDay-1, counterMask)
+	if !valid.InRange() {
+		t.Fatalf("expected %v to be in range", valid)
+	}
+
+	badMinute := ID(uint64(minutesPerDay) << counterBits)
+	if badMinute.InRange() {
+		t.Fatalf("expected minute %d to be out of range", minutesPerDay)
+	}
+	if ID(maxValue + 1).InRange() {
+		t.Fatalf("expected value beyond %d bits to be out of range", totalBits)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		name string
+		id   ID
+		want error
+	}{
+		{"zero", 0, nil},
+		{"golden", 56755782866, nil},
+		{"last minute", ID(daysMask<<(minutesBits+counterBits) | (minutesPerDay-1)<<counterBits | counterMask), nil},
+		{"beyond 40 bits", maxValue + 1, errOverflow},
+		{"minute 1440", ID(uint64(minutesPerDay) << counterBits), ErrInvalidMinute},
+		{"minute 2047", maxValue, ErrInvalidMinute},
+	}
+	for _, tc := range cases {
+		err := tc.id.Validate()
+		if tc.want == nil && err != nil {
+			t.Fatalf("%s: unexpected error %v", tc.name, err)
+		}
+		if tc.want != nil && !errors.Is(err, tc.want) {
+			t.Fatalf("%s: got %v want %v", tc.name, err, tc.want)
+		}
+	}
+}
+
+func TestParseMinuteBoundary(t *testing.T) {
+	last, _ := FromComponents(0, minutesPerDay-1, 0)
+	if _, err := Parse(last.String()); err != nil {
+		t.Fatalf("Parse minute %d error: %v", minutesPerDay-1, err)
+	}
+
+	invalid := ID(uint64(minutesPerDay) << counterBits)
+	if _, err := Parse(invalid.String()); !errors.Is(err, ErrInvalidMinute) {
+		t.Fatalf("expected ErrInvalidMinute for minute %d, got %v", minutesPerDay, err)
+	}
+	if _, err := Parse("ZZZZZZZZ"); !errors.Is(err, ErrInvalidMinute) {
+		t.Fatalf("expected ErrInvalidMinute, got %v", err)
+	}
+}
+
+// encodeLoop is the reference loop encoder that encode unrolls.
+func encodeLoop(id ID) string {
+	var buf [totalSize]byte
+	value := uint64(id)
+	for i := totalSize - 1; i >= 0; i-- {
+		buf[i] = encodeAlphabet[int(value&31)]
+		value >>= 5
+	}
+	return string(buf[:])
+}
+
+func TestStringMatchesLoop(t *testing.T) {
+	for _, id := range []ID{0, 1, 31, 32, 56755782866, maxValue >> 1, maxValue} {
+		if got, want := id.String(), encodeLoop(id); got != want {
+			t.Fatalf("String(%d): got %q want %q", uint64(id), got, want)
+		}
+	}
+}
+
+func BenchmarkString(b *testing.B) {
+	id := ID(56755782866)
+	for b.Loop() {
+		_ = id.String()
+	}
+}
+
+func BenchmarkStringLoop(b *testing.B) {
+	id := ID(56755782866)
+	for b.Loop() {
+		_ = encodeLoop(id)
+	}
+}
+
+func TestJoinStrings(t *testing.T) {
+	ids := []ID{0, 7, 56755782866, maxValue}
+	want := make([]string, len(ids))
+	for i, id := range ids {
+		want[i] = id.String()
+	}
+
+	if got := Strings(ids); !slices.Equal(got, want) {
+		t.Fatalf("Strings: got %q want %q", got, want)
+	}
+	for _, sep := range []string{"", ",", "\n", " | "} {
+		if got := JoinStrings(ids, sep); got != strings.Join(want, sep) {
+			t.Fatalf("JoinStrings(%q): got %q", sep, got)
+		}
+	}
+	if got := JoinStrings(nil, ","); got != "" {
+		t.Fatalf("JoinStrings(nil): got %q", got)
+	}
+	if got := Strings(nil); len(got) != 0 {
+		t.Fatalf("Strings(nil): got %q", got)
+	}
+	if allocs := testing.AllocsPerRun(10, func() { JoinStrings(ids, ",") }); allocs != 1 {
+		t.Fatalf("JoinStrings allocations: got %v want 1", allocs)
+	}
+}
+
+func benchmarkIDs() []ID {
+	ids := make([]ID, 1024)
+	for i := range ids {
+		ids[i] = ID(56755782866 + i)
+	}
+	return ids
+}
+
+func TestEncodeAllocations(t *testing.T) {
+	id := ID(56755782866)
+	ids := benchmarkIDs()
+	buf := make([]byte, 0, EncodedLen)
+	var sink string
+	var sinks []string
+	cases := []struct {
+		name string
+		fn   func()
+		want float64
+	}{
+		{"String", func() { sink = id.String() }, 1},
+		{"AppendText", func() { buf, _ = id.AppendText(buf[:0]) }, 0},
+		{"Strings", func() { sinks = Strings(ids) }, 2},
+		{"JoinStrings", func() { sink = JoinStrings(ids, ",") }, 1},
+	}
+	for _, tc := range cases {
+		if got := testing.AllocsPerRun(20, tc.fn); got != tc.want {
+			t.Fatalf("%s allocations: got %v want %v", tc.name, got, tc.want)
+		}
+	}
+	_, _ = sink, sinks
+}
+
+func BenchmarkStrings(b *testing.B) {
+	ids := benchmarkIDs()
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = Strings(ids)
+	}
+}
+
+func BenchmarkStringsLoop(b *testing.B) {
+	ids := benchmarkIDs()
+	b.ReportAllocs()
+	for b.Loop() {
+		strs := make([]string, len(ids))
+		for i, id := range ids {
+			strs[i] = id.String()
+		}
+	}
+}
+
+func BenchmarkJoinStrings(b *testing.B) {
+	ids := benchmarkIDs()
+	for b.Loop() {
+		_ = JoinStrings(ids, "\n")
+	}
+}
+
+func BenchmarkJoinStringsLoop(b *testing.B) {
+	ids := benchmarkIDs()
+	for b.Loop() {
+		strs := make([]string, len(ids))
+		for i, id := range ids {
+			strs[i] = id.String()
+		}
+		_ = strings.Join(strs, "\n")
+	}
+}
+
+func TestParseMany(t *testing.T) {
+	ids, err := ParseMany([]byte("00000007\n1MVEH16J\n\n"), '\n')
+	if err != nil {
+		t.Fatalf("ParseMany error: %v", err)
+	}
+	if len(ids) != 2 || ids[0] != 7 || ids[1] != 56755782866 {
+		t.Fatalf("ParseMany: got %v", ids)
+	}
+
+	_, err = ParseMany([]byte("00000007,1MVEH16U,1MVEH16J"), ',')
+	if !errors.Is(err, errInvalidChar) {
+		t.Fatalf("expected errInvalidChar, got %v", err)
+	}
+	if !strings.Contains(err.Error(), `token 1 "1MVEH16U"`) {
+		t.Fatalf("error lacks token context: %v", err)
+	}
+
+	if ids, err := ParseMany(nil, '\n'); err != nil || len(ids) != 0 {
+		t.Fatalf("ParseMany(nil): got %v, %v", ids, err)
+	}
+}
+
+func TestGenerateWithComponentsZones(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("LoadLocation error: %v", err)
+	}
+	lordHowe, err := time.LoadLocation("Australia/Lord_Howe")
+	if err != nil {
+		t.Fatalf("LoadLocation error: %v", err)
+	}
+
+	instant := time.Date(2024, 3, 10, 6, 59, 30, 0, time.UTC)
+	cases := []struct {
+		name string
+		t    time.Time
+	}{
+		{"UTC", instant},
+		{"India +05:30", instant.In(time.FixedZone("IST", 5*3600+30*60))},
+		{"Nepal +05:45", instant.In(time.FixedZone("NPT", 5*3600+45*60))},
+		{"Chatham +12:45", instant.In(time.FixedZone("CHAST", 12*3600+45*60))},
+		{"Newfoundland -03:30", instant.In(time.FixedZone("NST", -(3*3600 + 30*60)))},
+		{"Marquesas -09:30", instant.In(time.FixedZone("MART", -(9*3600 + 30*60)))},
+		{"New York DST boundary", instant.In(newYork)},
+		{"Lord Howe half-hour DST", instant.In(lordHowe)},
+	}
+
+	want, err := GenerateWithComponents(instant, 7)
+	if err != nil {
+		t.Fatalf("GenerateWithComponents error: %v", err)
+	}
+	for _, tc := range cases {
+		got, err := GenerateWithComponents(tc.t, 7)
+		if err != nil {
+			t.Fatalf("%s: GenerateWithComponents error: %v", tc.name, err)
+		}
+		if got != want {
+			t.Fatalf("%s: got %s want %s", tc.name, got.Inspect(), want.Inspect())
+		}
+	}
+
+	// One second before and after the New York spring-forward gap.
+	before := time.Date(2024, 3, 10, 1, 59, 59, 0, newYork)
+	after := before.Add(time.Second)
+	idBefore, _ := GenerateWithComponents(before, 0)
+	idAfter, _ := GenerateWithComponents(after, 0)
+	if _, minutes, _ := idBefore.Components(); minutes != 6*60+59 {
+		t.Fatalf("minute before gap: got %d want %d", minutes, 6*60+59)
+	}
+	if _, minutes, _ := idAfter.Components(); minutes != 7*60 {
+		t.Fatalf("minute after gap: got %d want %d", minutes, 7*60)
+	}
+}
+
+func TestBuckets(t *testing.T) {
+	for _, ts := range []time.Time{
+		epoch,
+		time.Date(2024, 8, 18, 15, 30, 0, 0, time.UTC),
+		time.Date(2031, 12, 31, 23, 59, 0, 0, time.UTC),
+	} {
+		id, err := GenerateWithComponents(ts, 99)
+		if err != nil {
+			t.Fatalf("GenerateWithComponents error: %v", err)
+		}
+		if got, want := int(id.DayBucket()), int(id.Time().Sub(epoch)/(24*time.Hour)); got != want {
+			t.Fatalf("DayBucket(%v): got %d want %d", ts, got, want)
+		}
+		if got, want := id.HourBucket(), id.Time().Hour(); got != want {
+			t.Fatalf("HourBucket(%v): got %d want %d", ts, got, want)
+		}
+	}
+}
+
+func TestStringWithCheck(t *testing.T) {
+	id := ID(56755782866)
+	checked := id.StringWithCheck()
+	if len(checked) != EncodedLen+1 || checked[:EncodedLen] != id.String() {
+		t.Fatalf("StringWithCheck: got %q", checked)
+	}
+	if got, err := ParseWithCheck(checked); err != nil || got != id {
+		t.Fatalf("ParseWithCheck: got %v, %v", got, err)
+	}
+	if got, err := ParseWithCheck(strings.ToLower(checked)); err != nil || got != id {
+		t.Fatalf("ParseWithCheck lowercase: got %v, %v", got, err)
+	}
+
+	symbols := checkAlphabet
+	for i := 0; i < len(checked); i++ {
+		for j := 0; j < len(symbols); j++ {
+			if symbols[j] == checked[i] {
+				continue
+			}
+			flipped := checked[:i] + string(symbols[j]) + checked[i+1:]
+			if _, err := ParseWithCheck(flipped); err == nil {
+				t.Fatalf("flip %q at %d not detected: %q", symbols[j], i, flipped)
+			}
+		}
+	}
+
+	if _, err := ParseWithCheck(id.String() + "0"); !errors.Is(err, ErrChecksum) {
+		t.Fatalf("expected ErrChecksum, got %v", err)
+	}
+	if _, err := ParseWithCheck(id.String()); err == nil {
+		t.Fatalf("expected length error")
+	}
+}
+
+func TestParseLegacy(t *testing.T) {
+	minute, err := Parse("01ABZ9QT")
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	got, err := ParseLegacy("01ABZ9QT-0052")
+	if err != nil {
+		t.Fatalf("ParseLegacy error: %v", err)
+	}
+	if !got.SameMinute(minute) {
+		t.Fatalf("ParseLegacy minute: got %s want %s", got.Time(), minute.Time())
+	}
+	if _, _, counter := got.Components(); counter != 52 {
+		t.Fatalf("ParseLegacy counter: got %d want 52", counter)
+	}
+
+	for _, input := range []string{"01ABZ9QT", "01ABZ9QT-", "01ABZ9QT-5x", "01ABZ9QT--1", "01ABZ9QT-16384", "01ABZ9Q-0052"} {
+		if _, err := ParseLegacy(input); err == nil {
+			t.Fatalf("ParseLegacy(%q): expected error", input)
+		}
+	}
+	if _, err := Parse("01ABZ9QT-0052"); err == nil {
+		t.Fatalf("Parse must reject the dashed form")
+	}
+}
+
+func TestPattern(t *testing.T) {
+	re := regexp.MustCompile(Pattern())
+	if want := "^[0-9A-HJKMNP-TV-Za-hjkmnp-tv-z]{8}$"; re.String() != want {
+		t.Fatalf("Pattern: got %s want %s", re, want)
+	}
+	for _, good := range []string{"01ABZ9QT", "1MVEH16J", "1mveh16j"} {
+		if !re.MatchString(good) {
+			t.Fatalf("Pattern %s rejects %q", re, good)
+		}
+	}
+	for _, bad := range []string{"01ABZ9Q", "01ABZ9QTX", "01ABZ9QU", "01-BZ9QT", " 01ABZ9Q", "01ABZ9Q\n", "OLIO0000", "0lio0000"} {
+		if re.MatchString(bad) {
+			t.Fatalf("Pattern %s accepts %q", re, bad)
+		}
+	}
+	for c := 0; c < 256; c++ {
+		ok := strings.IndexByte(Alphabet, byte(c)) >= 0 || strings.IndexByte(strings.ToLower(Alphabet), byte(c)) >= 0
+		if got := re.MatchString(strings.Repeat(string(rune(c)), totalSize)); got != ok {
+			t.Fatalf("Pattern match for %q: got %v want %v", c, got, ok)
+		}
+	}
+}
+
+func TestParseLenient(t *testing.T) {
+	want, err := Parse("01ABZ9QT")
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	for _, input := range []string{`01ABZ9QT`, ` 01ABZ9QT `, `'01ABZ9QT'`, `"01ABZ9QT"`, "\t\"01ABZ9QT\"\n"} {
+		got, err := ParseLenient(input)
+		if err != nil {
+			t.Fatalf("ParseLenient(%q) error: %v", input, err)
+		}
+		if got != want {
+			t.Fatalf("ParseLenient(%q): got %v want %v", input, got, want)
+		}
+	}
+	for _, input := range []string{`"01ABZ9QT'`, `""01ABZ9QT""`, `" 01ABZ9QT "`} {
+		if _, err := ParseLenient(input); err == nil {
+			t.Fatalf("ParseLenient(%q): expected error", input)
+		}
+	}
+	if _, err := Parse(" 01ABZ9QT "); !errors.Is(err, errLength) {
+		t.Fatalf("Parse must stay strict, got %v", err)
+	}
+}
+
+func TestEqualConstantTime(t *testing.T) {
+	ids := []ID{0, 1, 56755782866, 56755782867, maxValue, maxValue + 1}
+	for _, a := range ids {
+		for _, b := range ids {
+			if got, want := a.EqualConstantTime(b), a == b; got != want {
+				t.Fatalf("EqualConstantTime(%d, %d): got %v want %v", a, b, got, want)
+			}
+		}
+	}
+}
+
+func TestCmpMinMax(t *testing.T) {
+	ids := []ID{56755782866, 7, maxValue, 0, 1234}
+	slices.SortFunc(ids, Cmp)
+	if !slices.Equal(ids, []ID{0, 7, 1234, 56755782866, maxValue}) {
+		t.Fatalf("SortFunc(Cmp): got %v", ids)
+	}
+	if got := Min(ids); got != 0 {
+		t.Fatalf("Min: got %d want 0", got)
+	}
+	if got := Max(ids); got != maxValue {
+		t.Fatalf("Max: got %d want %d", got, ID(maxValue))
+	}
+	if Cmp(1, 2) != -1 || Cmp(2, 2) != 0 || Cmp(3, 2) != 1 {
+		t.Fatalf("Cmp results inconsistent")
+	}
+}
+
+func TestRepresentableRange(t *testing.T) {
+	if !MinTime().Equal(epoch) {
+		t.Fatalf("MinTime: got %v want %v", MinTime(), epoch)
+	}
+	if want := time.Date(2109, 9, 18, 23, 59, 0, 0, time.UTC); !MaxTime().Equal(want) {
+		t.Fatalf("MaxTime: got %v want %v", MaxTime(), want)
+	}
+
+	cases := []struct {
+		t    time.Time
+		want bool
+	}{
+		{MinTime(), true},
+		{MinTime().Add(-time.Nanosecond), false},
+		{MaxTime(), true},
+		{MaxTime().Add(time.Minute - time.Nanosecond), true},
+		{MaxTime().Add(time.Minute), false},
+	}
+	for _, tc := range cases {
+		if got := Representable(tc.t); got != tc.want {
+			t.Fatalf("Representable(%v): got %v want %v", tc.t, got, tc.want)
+		}
+		_, err := GenerateWithComponents(tc.t, 0)
+		if got := err == nil; got != tc.want {
+			t.Fatalf("GenerateWithComponents(%v) disagrees with Representable: %v", tc.t, err)
+		}
+	}
+
+	last, err := GenerateWithComponents(MaxTime(), 0)
+	if err != nil {
+		t.Fatalf("GenerateWithComponents error: %v", err)
+	}
+	if !last.Time().Equal(MaxTime()) {
+		t.Fatalf("MaxTime round trip: got %v", last.Time())
+	}
+}
+
+func TestCursorAt(t *testing.T) {
+	ts := time.Date(2024, 8, 18, 15, 30, 42, 0, time.UTC)
+	cursor, err := CursorAt(ts)
+	if err != nil {
+		t.Fatalf("CursorAt error: %v", err)
+	}
+	minute := ts.Truncate(time.Minute)
+	if !cursor.Time().Equal(minute) {
+		t.Fatalf("cursor time: got %v want %v", cursor.Time(), minute)
+	}
+	if cursor.TruncateMinute() != cursor {
+		t.Fatalf("cursor is not the smallest ID of its minute: %s", cursor.Inspect())
+	}
+	before, err := cursor.Prev()
+	if err != nil {
+		t.Fatalf("Prev error: %v", err)
+	}
+	if !before.Time().Before(minute) {
+		t.Fatalf("ID below the cursor is not earlier: %v", before.Time())
+	}
+
+	if _, err := CursorAt(epoch.Add(-time.Minute)); !errors.Is(err, errTimePast) {
+		t.Fatalf("expected errTimePast, got %v", err)
+	}
+}
+
+func TestIsCanonical(t *testing.T) {
+	cases := map[string]bool{
+		"1MVEH16J": true,
+		"00000010": true,
+		"OOOOOO1O": false,
+		"0000001O": false,
+		"lMVEH16J": false,
+		"IMVEH16J": false,
+		"1mveh16j": false,
+		"1MVEH16":  false,
+		"ZZZZZZZZ": false,
+	}
+	for encoded, want := range cases {
+		if got := IsCanonical(encoded); got != want {
+			t.Fatalf("IsCanonical(%q): got %v want %v", encoded, got, want)
+		}
+	}
+}
+
+func TestHexString(t *testing.T) {
+	id := ID(56755782866)
+	if got, want := id.HexString(), "0d36e884d2"; got != want {
+		t.Fatalf("HexString: got %q want %q", got, want)
+	}
+	if got := ID(7).HexString(); got != "0000000007" {
+		t.Fatalf("HexString(7): got %q", got)
+	}
+	back, err := ParseHex(id.HexString())
+	if err != nil || back != id {
+		t.Fatalf("ParseHex: got %v, %v", back, err)
+	}
+
+	for _, input := range []string{"00d36e884d2", "0d36e884d", "0d36e884dg", "+d36e884d2", "ffffffffff"} {
+		if _, err := ParseHex(input); err == nil {
+			t.Fatalf("ParseHex(%q): expected error", input)
+		}
+	}
+}
+
+func TestExampleID(t *testing.T) {
+	if got, want := ExampleID(0).Time(), time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Fatalf("ExampleID(0) time: got %v want %v", got, want)
+	}
+	if got := ExampleID(3).String(); got != "1DN1QR01" {
+		t.Fatalf("ExampleID(3) is not stable: got %q", got)
+	}
+
+	ids := make([]ID, 5000)
+	for n := range ids {
+		ids[n] = ExampleID(n)
+		if !ids[n].InRange() {
+			t.Fatalf("ExampleID(%d) out of range: %s", n, ids[n].Inspect())
+		}
+		if ids[n] != ExampleID(n) {
+			t.Fatalf("ExampleID(%d) is not deterministic", n)
+		}
+	}
+	if HasDuplicates(ids) {
+		t.Fatalf("ExampleID produced duplicates")
+	}
+	if !ExampleID(1<<20 - 1).InRange() {
+		t.Fatalf("last ExampleID out of range")
+	}
+}
+
+func TestGenerateSequence(t *testing.T) {
+	start := time.Date(2024, 8, 18, 15, 30, 45, 0, time.UTC)
+	n := 1<<counterBits + 100
+	a, err := GenerateSequence(start, n)
+	if err != nil {
+		t.Fatalf("GenerateSequence error: %v", err)
+	}
+	b, err := GenerateSequence(start, n)
+	if err != nil {
+		t.Fatalf("GenerateSequence error: %v", err)
+	}
+	if !slices.Equal(a, b) {
+		t.Fatalf("GenerateSequence is not deterministic")
+	}
+	if len(a) != n || HasDuplicates(a) || !slices.IsSorted(a) {
+		t.Fatalf("GenerateSequence: want %d unique ascending IDs", n)
+	}
+
+	first, last := a[0], a[len(a)-1]
+	if _, _, counter := first.Components(); counter != 0 || !first.MatchesTime(start) {
+		t.Fatalf("first ID: got %s", first.Inspect())
+	}
+	if _, _, counter := last.Components(); counter != 99 || !last.MatchesTime(start.Add(time.Minute)) {
+		t.Fatalf("last ID: got %s", last.Inspect())
+	}
+
+	atEpoch, err := GenerateSequence(epoch, 2)
+	if err != nil || !slices.Equal(atEpoch, []ID{1, 2}) {
+		t.Fatalf("GenerateSequence at the epoch: got %v, %v want [1 2]", atEpoch, err)
+	}
+}
+
+func TestQuintets(t *testing.T) {
+	for _, id := range []ID{0, 7, 56755782866, maxValue} {
+		q := id.Quintets()
+		encoded := id.String()
+		for i := range q {
+			if want := decodeAlphabet[encoded[i]]; q[i] != want {
+				t.Fatalf("Quintets(%s)[%d]: got %d want %d", encoded, i, q[i], want)
+			}
+		}
+	}
+}
+
+func TestFutureBoundary(t *testing.T) {
+	lastDay := epoch.AddDate(0, 0, daysMask)
+	lastMinute := lastDay.Add((minutesPerDay - 1) * time.Minute)
+	if !lastMinute.Equal(MaxTime()) {
+		t.Fatalf("MaxTime: got %v want %v", MaxTime(), lastMinute)
+	}
+
+	id, err := GenerateWithComponents(lastMinute.Add(59*time.Second), counterMask)
+	if err != nil {
+		t.Fatalf("GenerateWithComponents at the last minute error: %v", err)
+	}
+	if want, _ := FromComponents(daysMask, minutesPerDay-1, counterMask); id != want {
+		t.Fatalf("last ID: got %s want %s", id.Inspect(), want.Inspect())
+	}
+	if parsed, err := Parse(id.String()); err != nil || parsed != id {
+		t.Fatalf("Parse of last ID: got %v, %v", parsed, err)
+	}
+	if _, err := id.Next(); err != nil {
+		t.Fatalf("Next of last valid ID error: %v", err)
+	}
+
+	if _, err := GenerateWithComponents(lastMinute.Add(time.Minute), 0); !errors.Is(err, errTimeFuture) {
+		t.Fatalf("expected errTimeFuture one minute past MaxTime, got %v", err)
+	}
+}
+
+func TestParseComponents(t *testing.T) {
+	for _, encoded := range []string{"00000007", "1MVEH16J", "1mveh16j"} {
+		days, minuteOfDay, counter, err := ParseComponents(encoded)
+		if err != nil {
+			t.Fatalf("ParseComponents(%q) error: %v", encoded, err)
+		}
+		id, _ := Parse(encoded)
+		wantDays, wantMinute, wantCounter := id.Components()
+		if days != wantDays || minuteOfDay != wantMinute || counter != wantCounter {
+			t.Fatalf("ParseComponents(%q): got %d/%d/%d want %d/%d/%d",
+				encoded, days, minuteOfDay, counter, wantDays, wantMinute, wantCounter)
+		}
+	}
+	if _, _, _, err := ParseComponents("ZZZZZZZZ"); !errors.Is(err, ErrInvalidMinute) {
+		t.Fatalf("expected ErrInvalidMinute, got %v", err)
+	}
+}
+
+func FuzzParse(f *testing.F) {
+	for _, seed := range []string{"1MVEH16J", "1mveh16j", "00000000", "ZZZZZZZZ", "01ABZ9QT", "0O1IL000", "İ000000", "1MVEH16"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		id, err := Parse(s)
+		if err != nil {
+			return
+		}
+		if !id.InRange() {
+			t.Fatalf("Parse(%q) accepted out-of-range %s", s, id.Inspect())
+		}
+		back, err := Parse(id.String())
+		if err != nil || back != id {
+			t.Fatalf("round trip of %q: got %v, %v want %v", s, back, err, id)
+		}
+		if len(s) != EncodedLen || !IsCanonical(strings.ToUpper(s)) && !strings.ContainsAny(s, "ILOilo") {
+			t.Fatalf("Parse(%q) accepted a non-Crockford input", s)
+		}
+	})
+}
+
+func FuzzInt64(f *testing.F) {
+	for _, seed := range []int64{0, 1, 56755782866, maxValue, maxValue + 1, -1, 1440 << counterBits} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, v int64) {
+		id, err := FromInt64(v)
+		if err != nil {
+			return
+		}
+		if id.Int64() != v {
+			t.Fatalf("FromInt64(%d).Int64() = %d", v, id.Int64())
+		}
+		// Time and Components must not panic on any 40-bit value, including
+		// minute fields above 1439.
+		_ = id.Time()
+		_, _, _ = id.Components()
+		_ = id.String()
+	})
+}
@@ -0,0 +1,16 @@
+module github.com/chisenberg/mini-ulid/miniulidgorm
+
+go 1.24.4
+
+require (
+	github.com/chisenberg/mini-ulid v0.0.0
+	gorm.io/gorm v1.31.2
+)
+
+require (
+	github.com/jinzhu/inflection v1.0.0 // indirect
+	github.com/jinzhu/now v1.1.5 // indirect
+	golang.org/x/text v0.34.0 // indirect
+)
+
+replace github.com/chisenberg/mini-ulid => ../
@@ -0,0 +1,161 @@
+// Package miniulidgorm integrates miniulid IDs with GORM.
+//
+// miniulid.ID fields need no registration to be stored as integers: ID
+// implements driver.Valuer and sql.Scanner, so GORM maps them to a 64-bit
+// integer column. GORM makes an integer primary key auto-increment unless told
+// otherwise, so tag ID primary keys with autoIncrement:false. To store a column
+// as the 8-character encoded form instead, tag it with the miniulid serializer:
+//
+//	type Order struct {
+//		ID       miniulid.ID `gorm:"primaryKey;autoIncrement:false"`
+//		Ref      miniulid.ID `gorm:"serializer:miniulid;size:8" miniulid:"auto"`
+//		ParentID miniulid.ID
+//	}
+//
+// Install the Plugin to generate IDs on create:
+//
+//	db.Use(&miniulidgorm.Plugin{})
+//
+// It fills zero-valued ID primary keys, and ID or *ID fields tagged
+// miniulid:"auto", before each insert. A nil *ID gets a new ID; a non-nil one
+// is kept even if it points to the zero ID.
+package miniulidgorm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	miniulid "github.com/chisenberg/mini-ulid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// SerializerName is the name Serializer is registered under, for use in
+// serializer:miniulid field tags.
+const SerializerName = "miniulid"
+
+func init() {
+	schema.RegisterSerializer(SerializerName, Serializer{})
+}
+
+var idType = reflect.TypeOf(miniulid.ID(0))
+
+// Serializer stores miniulid.ID and *miniulid.ID fields as their encoded
+// string. Scanning accepts any form ID.Scan does, so a column may switch from
+// integer to string storage without rewriting rows first.
+type Serializer struct{}
+
+// Scan implements schema.SerializerInterface. NULL leaves the field at its
+// zero value.
+func (Serializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue any) error {
+	fieldValue := reflect.New(field.FieldType).Elem()
+	if dbValue != nil {
+		var id miniulid.ID
+		if err := id.Scan(dbValue); err != nil {
+			return err
+		}
+		v := fieldValue
+		if v.Kind() == reflect.Pointer {
+			v.Set(reflect.New(idType))
+			v = v.Elem()
+		}
+		v.Set(reflect.ValueOf(id))
+	}
+	field.ReflectValueOf(ctx, dst).Set(fieldValue)
+	return nil
+}
+
+// Value implements schema.SerializerValuerInterface. A nil *miniulid.ID is
+// stored as NULL.
+func (Serializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue any) (any, error) {
+	switch v := fieldValue.(type) {
+	case miniulid.ID:
+		return v.String(), nil
+	case *miniulid.ID:
+		if v == nil {
+			return nil, nil
+		}
+		return v.String(), nil
+	default:
+		return nil, fmt.Errorf("miniulidgorm: cannot serialize %T as an ID", fieldValue)
+	}
+}
+
+// Plugin is a gorm.Plugin that generates IDs for records being created.
+type Plugin struct {
+	// Generator issues the IDs. Nil uses the package-level generator that
+	// miniulid.Generate draws from at the time of each insert.
+	Generator *miniulid.Generator
+}
+
+// Name implements gorm.Plugin.
+func (p *Plugin) Name() string {
+	return "miniulid"
+}
+
+// Initialize implements gorm.Plugin, registering a callback that runs after
+// the models' BeforeCreate hooks and before the insert.
+func (p *Plugin) Initialize(db *gorm.DB) error {
+	return db.Callback().Create().Before("gorm:create").Register("miniulid:generate", p.generate)
+}
+
+func (p *Plugin) generate(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Schema == nil {
+		return
+	}
+	var fields []*schema.Field
+	for _, field := range db.Statement.Schema.Fields {
+		if field.IndirectFieldType == idType && (field.PrimaryKey || field.Tag.Get("miniulid") == "auto") {
+			fields = append(fields, field)
+		}
+	}
+	if len(fields) == 0 {
+		return
+	}
+
+	rv := reflect.Indirect(db.Statement.ReflectValue)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			if err := p.fill(db.Statement.Context, fields, reflect.Indirect(rv.Index(i))); err != nil {
+				db.AddError(err)
+				return
+			}
+		}
+	case reflect.Struct:
+		if err := p.fill(db.Statement.Context, fields, rv); err != nil {
+			db.AddError(err)
+		}
+	}
+}
+
+func (p *Plugin) fill(ctx context.Context, fields []*schema.Field, rv reflect.Value) error {
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+	for _, field := range fields {
+		if _, isZero := field.ValueOf(ctx, rv); !isZero {
+			continue
+		}
+		id, err := p.next()
+		if err != nil {
+			return err
+		}
+		var value any = id
+		if field.FieldType.Kind() == reflect.Pointer {
+			value = &id
+		}
+		if err := field.Set(ctx, rv, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *Plugin) next() (miniulid.ID, error) {
+	if p.Generator == nil {
+		return miniulid.Generate()
+	}
+	return p.Generator.Generate()
+}
@@ -0,0 +1,150 @@
+package miniulidgorm
+
+import (
+	"context"
+	"database/sql/driver"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+
+	miniulid "github.com/chisenberg/mini-ulid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/callbacks"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// dryRunDialector builds statements without a database connection.
+type dryRunDialector struct{}
+
+func (dryRunDialector) Name() string { return "dryrun" }
+
+func (dryRunDialector) Initialize(db *gorm.DB) error {
+	callbacks.RegisterDefaultCallbacks(db, &callbacks.Config{})
+	return nil
+}
+
+func (dryRunDialector) Migrator(*gorm.DB) gorm.Migrator { return nil }
+
+func (dryRunDialector) DataTypeOf(*schema.Field) string { return "" }
+
+func (dryRunDialector) DefaultValueOf(*schema.Field) clause.Expression {
+	return clause.Expr{SQL: "DEFAULT"}
+}
+
+func (dryRunDialector) BindVarTo(w clause.Writer, stmt *gorm.Statement, _ any) {
+	w.WriteString("$" + strconv.Itoa(len(stmt.Vars)))
+}
+
+func (dryRunDialector) QuoteTo(w clause.Writer, s string) { w.WriteString(s) }
+
+func (dryRunDialector) Explain(sql string, _ ...any) string { return sql }
+
+type order struct {
+	ID       miniulid.ID  `gorm:"primaryKey;autoIncrement:false"`
+	Ref      miniulid.ID  `gorm:"serializer:miniulid;size:8" miniulid:"auto"`
+	ParentID *miniulid.ID `gorm:"serializer:miniulid"`
+	Other    miniulid.ID
+	TraceID  *miniulid.ID `gorm:"serializer:miniulid" miniulid:"auto"`
+}
+
+func openDryRun(t *testing.T, p *Plugin) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(dryRunDialector{}, &gorm.Config{DryRun: true})
+	if err != nil {
+		t.Fatalf("open error: %v", err)
+	}
+	if err := db.Use(p); err != nil {
+		t.Fatalf("use plugin error: %v", err)
+	}
+	return db
+}
+
+func TestPluginGenerates(t *testing.T) {
+	db := openDryRun(t, &Plugin{Generator: miniulid.NewGenerator()})
+
+	o := order{Ref: miniulid.ID(56755782866)}
+	stmt := db.Create(&o).Statement
+	if stmt.Error != nil {
+		t.Fatalf("create error: %v", stmt.Error)
+	}
+	if o.ID == 0 {
+		t.Fatalf("expected primary key to be generated")
+	}
+	if o.Ref != miniulid.ID(56755782866) {
+		t.Fatalf("non-zero tagged field was overwritten: %v", o.Ref)
+	}
+	if o.Other != 0 || o.ParentID != nil {
+		t.Fatalf("untagged fields were generated: %v, %v", o.Other, o.ParentID)
+	}
+	if o.TraceID == nil || *o.TraceID == 0 || *o.TraceID == o.ID {
+		t.Fatalf("tagged pointer field was not generated: %v", o.TraceID)
+	}
+	var vars []any
+	for _, v := range stmt.Vars {
+		if valuer, ok := v.(driver.Valuer); ok {
+			v, _ = valuer.Value()
+		}
+		vars = append(vars, v)
+	}
+	want := []any{o.ID.Int64(), o.Ref.String(), nil, int64(0), o.TraceID.String()}
+	if !reflect.DeepEqual(vars, want) {
+		t.Fatalf("insert values: got %#v want %#v", vars, want)
+	}
+
+	batch := make([]order, 3)
+	if err := db.Create(&batch).Error; err != nil {
+		t.Fatalf("batch create error: %v", err)
+	}
+	seen := map[miniulid.ID]bool{}
+	for _, o := range batch {
+		for _, id := range []miniulid.ID{o.ID, o.Ref, *o.TraceID} {
+			if id == 0 || seen[id] {
+				t.Fatalf("batch ID %v is zero or duplicated", id)
+			}
+			seen[id] = true
+		}
+	}
+
+	var zero miniulid.ID
+	kept := order{TraceID: &zero}
+	if err := db.Create(&kept).Error; err != nil {
+		t.Fatalf("create error: %v", err)
+	}
+	if kept.TraceID != &zero || zero != 0 {
+		t.Fatalf("non-nil pointer field was replaced: %v", kept.TraceID)
+	}
+}
+
+func TestSerializerScan(t *testing.T) {
+	s, err := schema.Parse(&order{}, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatalf("parse schema error: %v", err)
+	}
+	ctx := context.Background()
+	id := miniulid.ID(56755782866)
+
+	for _, src := range []any{id.String(), []byte(id.String()), id.Int64()} {
+		var o order
+		rv := reflect.ValueOf(&o).Elem()
+		if err := s.LookUpField("Ref").Serializer.Scan(ctx, s.LookUpField("Ref"), rv, src); err != nil {
+			t.Fatalf("scan %#v error: %v", src, err)
+		}
+		if err := s.LookUpField("ParentID").Serializer.Scan(ctx, s.LookUpField("ParentID"), rv, src); err != nil {
+			t.Fatalf("scan %#v into pointer error: %v", src, err)
+		}
+		if o.Ref != id || o.ParentID == nil || *o.ParentID != id {
+			t.Fatalf("scan %#v: got %v, %v want %v", src, o.Ref, o.ParentID, id)
+		}
+	}
+
+	o := order{ParentID: &id}
+	rv := reflect.ValueOf(&o).Elem()
+	if err := s.LookUpField("ParentID").Serializer.Scan(ctx, s.LookUpField("ParentID"), rv, nil); err != nil || o.ParentID != nil {
+		t.Fatalf("scan NULL: got %v, %v want nil", o.ParentID, err)
+	}
+	if err := s.LookUpField("Ref").Serializer.Scan(ctx, s.LookUpField("Ref"), rv, id.String()[:7]+"!"); err == nil {
+		t.Fatalf("expected error scanning an invalid ID")
+	}
+}
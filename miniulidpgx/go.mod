@@ -0,0 +1,10 @@
+module github.com/chisenberg/mini-ulid/miniulidpgx
+
+go 1.25.0
+
+require (
+	github.com/chisenberg/mini-ulid v0.0.0
+	github.com/jackc/pgx/v5 v5.9.2
+)
+
+replace github.com/chisenberg/mini-ulid => ../
@@ -0,0 +1,73 @@
+// Package miniulidpgx integrates miniulid IDs with pgx's binary protocol.
+//
+// Register the ID encoder on each connection's type map, for example from
+// pgxpool's AfterConnect hook:
+//
+//	config.AfterConnect = func(ctx context.Context, conn *pgx.Conn) error {
+//		miniulidpgx.Register(conn.TypeMap())
+//		return nil
+//	}
+//
+// IDs then encode natively, including in CopyFrom, as bigint for int8 columns
+// and as the 5-byte big-endian form for bytea columns. Scanning needs no
+// registration: pgx hands the decoded int64 or []byte to ID.Scan, which
+// validates it.
+package miniulidpgx
+
+import (
+	miniulid "github.com/chisenberg/mini-ulid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Register makes m encode miniulid.ID values, and pointers to them, without
+// going through driver.Valuer, so that bytea columns receive the binary form.
+func Register(m *pgtype.Map) {
+	m.TryWrapEncodePlanFuncs = append([]pgtype.TryWrapEncodePlanFunc{tryWrapIDEncodePlan}, m.TryWrapEncodePlanFuncs...)
+}
+
+func tryWrapIDEncodePlan(value any) (pgtype.WrappedEncodePlanNextSetter, any, bool) {
+	switch v := value.(type) {
+	case miniulid.ID:
+		return &idEncodePlan{}, idValue(v), true
+	case *miniulid.ID:
+		// pgx only dereferences pointers that are not driver.Valuers; nil
+		// pointers never reach the plan.
+		return &idPtrEncodePlan{}, idValue(*v), true
+	}
+	return nil, nil, false
+}
+
+type idEncodePlan struct {
+	next pgtype.EncodePlan
+}
+
+func (p *idEncodePlan) SetNext(next pgtype.EncodePlan) {
+	p.next = next
+}
+
+func (p *idEncodePlan) Encode(value any, buf []byte) ([]byte, error) {
+	return p.next.Encode(idValue(value.(miniulid.ID)), buf)
+}
+
+// idValue presents an ID to the int8 and bytea codecs.
+type idValue miniulid.ID
+
+func (v idValue) Int64Value() (pgtype.Int8, error) {
+	return pgtype.Int8{Int64: miniulid.ID(v).Int64(), Valid: true}, nil
+}
+
+func (v idValue) BytesValue() ([]byte, error) {
+	return miniulid.ID(v).MarshalBinary()
+}
+
+type idPtrEncodePlan struct {
+	next pgtype.EncodePlan
+}
+
+func (p *idPtrEncodePlan) SetNext(next pgtype.EncodePlan) {
+	p.next = next
+}
+
+func (p *idPtrEncodePlan) Encode(value any, buf []byte) ([]byte, error) {
+	return p.next.Encode(idValue(*value.(*miniulid.ID)), buf)
+}
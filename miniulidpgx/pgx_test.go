@@ -0,0 +1,61 @@
+package miniulidpgx
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	miniulid "github.com/chisenberg/mini-ulid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestEncodeScan(t *testing.T) {
+	m := pgtype.NewMap()
+	Register(m)
+	id := miniulid.ID(56755782866)
+
+	int8Buf, err := m.Encode(pgtype.Int8OID, pgtype.BinaryFormatCode, id, nil)
+	if err != nil {
+		t.Fatalf("encode int8 error: %v", err)
+	}
+	if got := int64(binary.BigEndian.Uint64(int8Buf)); got != id.Int64() {
+		t.Fatalf("int8 encoding: got %d want %d", got, id.Int64())
+	}
+
+	byteaBuf, err := m.Encode(pgtype.ByteaOID, pgtype.BinaryFormatCode, &id, nil)
+	if err != nil {
+		t.Fatalf("encode bytea error: %v", err)
+	}
+	if want, _ := id.MarshalBinary(); !bytes.Equal(byteaBuf, want) {
+		t.Fatalf("bytea encoding: got % x want % x", byteaBuf, want)
+	}
+
+	textBuf, err := m.Encode(pgtype.Int8OID, pgtype.TextFormatCode, id, nil)
+	if err != nil || string(textBuf) != "56755782866" {
+		t.Fatalf("int8 text encoding: got %q, %v", textBuf, err)
+	}
+
+	for _, tc := range []struct {
+		oid uint32
+		src []byte
+	}{
+		{pgtype.Int8OID, int8Buf},
+		{pgtype.ByteaOID, byteaBuf},
+	} {
+		var got miniulid.ID
+		if err := m.Scan(tc.oid, pgtype.BinaryFormatCode, tc.src, &got); err != nil || got != id {
+			t.Fatalf("scan oid %d: got %v, %v want %v", tc.oid, got, err, id)
+		}
+	}
+
+	var nilID *miniulid.ID
+	if buf, err := m.Encode(pgtype.ByteaOID, pgtype.BinaryFormatCode, nilID, nil); err != nil || buf != nil {
+		t.Fatalf("encode nil pointer: got % x, %v want NULL", buf, err)
+	}
+
+	var got miniulid.ID
+	invalid := binary.BigEndian.AppendUint64(nil, 1<<41)
+	if err := m.Scan(pgtype.Int8OID, pgtype.BinaryFormatCode, invalid, &got); err == nil {
+		t.Fatalf("expected error scanning a value beyond 40 bits")
+	}
+}
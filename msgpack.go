@@ -0,0 +1,31 @@
+package miniulid
+
+import "fmt"
+
+// MsgpackExtType is the msgpack extension type used for IDs.
+const MsgpackExtType int8 = 40
+
+const msgpackExt8 = 0xc7
+
+var errMsgpack = fmt.Errorf("miniulid: msgpack value must be extension %d with %d bytes", MsgpackExtType, binarySize)
+
+// MarshalMsgpack encodes the ID as a msgpack extension of type MsgpackExtType
+// carrying the 5-byte compact form. It satisfies the vmihailenco/msgpack
+// Marshaler interface.
+func (id ID) MarshalMsgpack() ([]byte, error) {
+	buf := make([]byte, 3+binarySize)
+	buf[0] = msgpackExt8
+	buf[1] = binarySize
+	buf[2] = byte(MsgpackExtType)
+	id.putBytes(buf[3:])
+	return buf, nil
+}
+
+// UnmarshalMsgpack decodes an extension produced by MarshalMsgpack.
+func (id *ID) UnmarshalMsgpack(data []byte) error {
+	if len(data) != 3+binarySize || data[0] != msgpackExt8 || data[1] != binarySize || int8(data[2]) != MsgpackExtType {
+		return errMsgpack
+	}
+	*id = idFromBytes(data[3:])
+	return nil
+}
@@ -0,0 +1,34 @@
+package miniulid
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMsgpackRoundTrip(t *testing.T) {
+	id := ID(56755782866)
+	data, err := id.MarshalMsgpack()
+	if err != nil {
+		t.Fatalf("MarshalMsgpack error: %v", err)
+	}
+	want := []byte{0xc7, 0x05, 0x28, 0x0d, 0x36, 0xe8, 0x84, 0xd2}
+	if string(data) != string(want) {
+		t.Fatalf("MarshalMsgpack: got % x want % x", data, want)
+	}
+
+	var back ID
+	if err := back.UnmarshalMsgpack(data); err != nil {
+		t.Fatalf("UnmarshalMsgpack error: %v", err)
+	}
+	if back != id {
+		t.Fatalf("msgpack mismatch: got %v want %v", back, id)
+	}
+
+	wrongType := append([]byte(nil), data...)
+	wrongType[2] = 1
+	for _, input := range [][]byte{wrongType, data[:len(data)-1], {0xcf, 0, 0, 0, 0, 0, 0, 0, 1}} {
+		if err := back.UnmarshalMsgpack(input); !errors.Is(err, errMsgpack) {
+			t.Fatalf("expected errMsgpack for % x, got %v", input, err)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package miniulid
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"log/slog"
+)
+
+// NullID is an ID that may be null. It mirrors sql.NullString: Valid is false
+// for SQL NULL and JSON null.
+type NullID struct {
+	ID    ID
+	Valid bool
+}
+
+// NullIDFrom returns a NullID holding *p, or an invalid NullID if p is nil,
+// for code moving from *ID fields.
+func NullIDFrom(p *ID) NullID {
+	if p == nil {
+		return NullID{}
+	}
+	return NullID{ID: *p, Valid: true}
+}
+
+// Ptr returns a pointer to a copy of the ID, or nil when Valid is false.
+func (n NullID) Ptr() *ID {
+	if !n.Valid {
+		return nil
+	}
+	id := n.ID
+	return &id
+}
+
+// Scan implements sql.Scanner. A NULL column sets Valid to false.
+func (n *NullID) Scan(src any) error {
+	if src == nil {
+		n.ID, n.Valid = 0, false
+		return nil
+	}
+	id, err := scanID(src)
+	if err != nil {
+		return err
+	}
+	n.ID, n.Valid = id, true
+	return nil
+}
+
+// Value implements driver.Valuer, returning nil when Valid is false and the
+// 40-bit integer otherwise.
+func (n NullID) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.ID.Int64(), nil
+}
+
+// String returns the encoded ID, or "" when Valid is false, so an unset field
+// is not mistaken for the epoch ID 00000000.
+func (n NullID) String() string {
+	if !n.Valid {
+		return ""
+	}
+	return n.ID.String()
+}
+
+// LogValue implements slog.LogValuer, logging the same text as String.
+func (n NullID) LogValue() slog.Value {
+	return slog.StringValue(n.String())
+}
+
+// MarshalJSON encodes null when Valid is false and the Crockford string otherwise.
+func (n NullID) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return n.ID.MarshalJSON()
+}
+
+// UnmarshalJSON decodes null as an invalid NullID and a string as a valid one.
+func (n *NullID) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		n.ID, n.Valid = 0, false
+		return nil
+	}
+	if err := n.ID.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
@@ -0,0 +1,120 @@
+package miniulid
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+func TestNullIDSQL(t *testing.T) {
+	id := ID(56755782866)
+
+	var n NullID
+	for _, src := range []any{id.Int64(), id.String(), []byte(id.String())} {
+		if err := n.Scan(src); err != nil {
+			t.Fatalf("Scan(%#v) error: %v", src, err)
+		}
+		if !n.Valid || n.ID != id {
+			t.Fatalf("Scan(%#v): got %+v", src, n)
+		}
+	}
+	if v, err := n.Value(); err != nil || v != id.Int64() {
+		t.Fatalf("Value: got %v, %v", v, err)
+	}
+
+	if err := n.Scan(nil); err != nil {
+		t.Fatalf("Scan(nil) error: %v", err)
+	}
+	if n.Valid {
+		t.Fatalf("Scan(nil) left Valid set")
+	}
+	if v, err := n.Value(); err != nil || v != nil {
+		t.Fatalf("Value of NULL: got %v, %v", v, err)
+	}
+
+	if err := n.Scan(3.5); err == nil {
+		t.Fatalf("expected error scanning float64")
+	}
+}
+
+func TestNullIDJSON(t *testing.T) {
+	type payload struct {
+		Parent NullID `json:"parent"`
+	}
+
+	id := ID(56755782866)
+	data, err := json.Marshal(payload{Parent: NullID{ID: id, Valid: true}})
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	if want := `{"parent":"1MVEH16J"}`; string(data) != want {
+		t.Fatalf("Marshal: got %s want %s", data, want)
+	}
+	var back payload
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if !back.Parent.Valid || back.Parent.ID != id {
+		t.Fatalf("Unmarshal: got %+v", back.Parent)
+	}
+
+	data, err = json.Marshal(payload{})
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	if want := `{"parent":null}`; string(data) != want {
+		t.Fatalf("Marshal null: got %s want %s", data, want)
+	}
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatalf("Unmarshal null error: %v", err)
+	}
+	if back.Parent.Valid {
+		t.Fatalf("Unmarshal null left Valid set")
+	}
+}
+
+func TestNullIDLogValue(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+
+	logger.Info("event", "id", NullID{})
+	logger.Info("event", "id", NullID{ID: 0, Valid: true})
+	logger.Info("event", "id", NullID{ID: 56755782866, Valid: true})
+
+	want := "level=INFO msg=event id=\"\"\n" +
+		"level=INFO msg=event id=00000000\n" +
+		"level=INFO msg=event id=1MVEH16J\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("log output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestNullIDPointers(t *testing.T) {
+	var (
+		_ sql.Scanner   = (*NullID)(nil)
+		_ driver.Valuer = NullID{}
+	)
+
+	if n := NullIDFrom(nil); n.Valid || n.Ptr() != nil {
+		t.Fatalf("NullIDFrom(nil): got %+v", n)
+	}
+	id := ID(56755782866)
+	n := NullIDFrom(&id)
+	if !n.Valid || n.ID != id {
+		t.Fatalf("NullIDFrom: got %+v", n)
+	}
+	p := n.Ptr()
+	if p == nil || *p != id || p == &id {
+		t.Fatalf("Ptr: got %v", p)
+	}
+}
@@ -0,0 +1,44 @@
+package miniulid
+
+import "sync/atomic"
+
+// ParseMode selects which characters the decoding functions accept.
+type ParseMode uint32
+
+const (
+	// ParseLenientMode accepts lowercase letters and reads the ambiguous I, L,
+	// and O as 1, 1, and 0. It is the default.
+	ParseLenientMode ParseMode = iota
+	// ParseStrictMode accepts only the characters String produces: digits
+	// and uppercase letters of Alphabet.
+	ParseStrictMode
+)
+
+var parseMode atomic.Uint32
+
+// SetParseMode sets the parse mode for the whole process. It applies to Parse
+// and every function built on it, including Layout.Parse, ParseID48, and the
+// JSON, XML, and SQL decoders, but not to Normalize, ParseLenient,
+// ParseFuzzy, and ParseReport, which exist to accept and report lenient
+// input, nor to Codec. It is safe to call concurrently with parsing, though it
+// is best set once during startup.
+func SetParseMode(m ParseMode) {
+	parseMode.Store(uint32(m))
+}
+
+// CurrentParseMode returns the mode set by SetParseMode.
+func CurrentParseMode() ParseMode {
+	return ParseMode(parseMode.Load())
+}
+
+// String returns the mode name.
+func (m ParseMode) String() string {
+	switch m {
+	case ParseLenientMode:
+		return "lenient"
+	case ParseStrictMode:
+		return "strict"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,64 @@
+package miniulid
+
+import (
+	"errors"
+	"regexp"
+	"slices"
+	"testing"
+)
+
+func TestSetParseMode(t *testing.T) {
+	t.Cleanup(func() { SetParseMode(ParseLenientMode) })
+
+	want, err := Parse("01ABZ9QT")
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+
+	SetParseMode(ParseStrictMode)
+	if CurrentParseMode() != ParseStrictMode {
+		t.Fatalf("CurrentParseMode: got %v", CurrentParseMode())
+	}
+	for _, input := range []string{"01abz9qt", "O1ABZ9QT", "0IABZ9QT", "0LABZ9QT"} {
+		if _, err := Parse(input); !errors.Is(err, errInvalidChar) {
+			t.Fatalf("strict Parse(%q): expected errInvalidChar, got %v", input, err)
+		}
+	}
+	if got, err := Parse("01ABZ9QT"); err != nil || got != want {
+		t.Fatalf("strict Parse of canonical form: got %v, %v", got, err)
+	}
+	if got, err := Normalize("01abz9qt"); err != nil || got != "01ABZ9QT" {
+		t.Fatalf("Normalize in strict mode: got %q, %v", got, err)
+	}
+	if got, err := ParseLenient(" '01abz9qt' "); err != nil || got != want {
+		t.Fatalf("ParseLenient in strict mode: got %v, %v", got, err)
+	}
+	if got, fixes, err := ParseFuzzy("O1ABZ9QT"); err != nil || got != want || len(fixes) != 1 {
+		t.Fatalf("ParseFuzzy in strict mode: got %v, %v, %v", got, fixes, err)
+	}
+	if got, r, err := ParseReport("0labz9qt"); err != nil || got != want || !slices.Equal(r.Positions, []int{1}) {
+		t.Fatalf("ParseReport in strict mode: got %v, %v, %v", got, r, err)
+	}
+	if _, err := ParseWithCheck("01ABZ9QTH"); err != nil {
+		t.Fatalf("strict ParseWithCheck of canonical form: %v", err)
+	}
+	if _, err := ParseWithCheck("01ABZ9QTh"); err == nil {
+		t.Fatalf("strict ParseWithCheck accepted a lowercase check symbol")
+	}
+	if _, _, err := ParseEnvelope("001ABZ9QT"); err != nil {
+		t.Fatalf("strict ParseEnvelope of canonical form: %v", err)
+	}
+	for _, input := range []string{"o01ABZ9QT", "O01ABZ9QT"} {
+		if _, _, err := ParseEnvelope(input); !errors.Is(err, errInvalidChar) {
+			t.Fatalf("strict ParseEnvelope(%q): expected errInvalidChar, got %v", input, err)
+		}
+	}
+	if re := regexp.MustCompile(Pattern()); re.MatchString("01abz9qt") || !re.MatchString("01ABZ9QT") {
+		t.Fatalf("strict Pattern %s", re)
+	}
+
+	SetParseMode(ParseLenientMode)
+	if got, err := Parse("01abz9qt"); err != nil || got != want {
+		t.Fatalf("lenient Parse: got %v, %v", got, err)
+	}
+}
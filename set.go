@@ -0,0 +1,61 @@
+package miniulid
+
+import "slices"
+
+// Set is a set of IDs. The zero value is an empty set ready to use. A Set is
+// not safe for concurrent use.
+type Set struct {
+	m map[ID]struct{}
+}
+
+// NewSet returns a set holding ids.
+func NewSet(ids ...ID) *Set {
+	s := &Set{m: make(map[ID]struct{}, len(ids))}
+	for _, id := range ids {
+		s.m[id] = struct{}{}
+	}
+	return s
+}
+
+// Add inserts id, reporting whether it was not already present.
+func (s *Set) Add(id ID) bool {
+	if _, ok := s.m[id]; ok {
+		return false
+	}
+	if s.m == nil {
+		s.m = make(map[ID]struct{})
+	}
+	s.m[id] = struct{}{}
+	return true
+}
+
+// Contains reports whether id is in the set.
+func (s *Set) Contains(id ID) bool {
+	_, ok := s.m[id]
+	return ok
+}
+
+// Remove deletes id, reporting whether it was present.
+func (s *Set) Remove(id ID) bool {
+	if _, ok := s.m[id]; !ok {
+		return false
+	}
+	delete(s.m, id)
+	return true
+}
+
+// Len returns the number of IDs in the set.
+func (s *Set) Len() int {
+	return len(s.m)
+}
+
+// Sorted returns the members in ascending order, which is chronological for
+// LayoutTimeFirst IDs.
+func (s *Set) Sorted() []ID {
+	ids := make([]ID, 0, len(s.m))
+	for id := range s.m {
+		ids = append(ids, id)
+	}
+	slices.SortFunc(ids, ID.Compare)
+	return ids
+}
@@ -0,0 +1,50 @@
+package miniulid
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSet(t *testing.T) {
+	var s Set
+	if s.Contains(1) || s.Len() != 0 || s.Remove(1) {
+		t.Fatalf("zero Set is not empty")
+	}
+	if !s.Add(1) || s.Add(1) {
+		t.Fatalf("Add should report only the first insertion")
+	}
+	if !s.Contains(1) || s.Len() != 1 {
+		t.Fatalf("Set after Add: Len=%d", s.Len())
+	}
+	if !s.Remove(1) || s.Remove(1) || s.Contains(1) || s.Len() != 0 {
+		t.Fatalf("Remove did not delete the ID")
+	}
+
+	if n := NewSet(5, 5, 7).Len(); n != 2 {
+		t.Fatalf("NewSet dedup: got %d want 2", n)
+	}
+}
+
+func TestSetSorted(t *testing.T) {
+	start := time.Date(2024, 8, 18, 15, 30, 0, 0, time.UTC)
+	var want []ID
+	for m := range 5 {
+		id, err := GenerateWithComponents(start.Add(time.Duration(m)*time.Hour), uint16(100-m))
+		if err != nil {
+			t.Fatalf("GenerateWithComponents error: %v", err)
+		}
+		want = append(want, id)
+	}
+
+	s := NewSet(want[3], want[0], want[4], want[1], want[2])
+	got := s.Sorted()
+	if !slices.Equal(got, want) {
+		t.Fatalf("Sorted: got %v want %v", got, want)
+	}
+	for i := 1; i < len(got); i++ {
+		if !got[i].Time().After(got[i-1].Time()) {
+			t.Fatalf("Sorted not chronological at %d", i)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package miniulid
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+)
+
+// ErrSignature is returned by Signer.Verify when the signature does not match.
+var ErrSignature = errors.New("miniulid: invalid signature")
+
+const signedSize = 2 * totalSize
+
+// Signer binds IDs to a secret key so that forged IDs can be detected. A signed
+// ID is the encoded ID followed by 8 more Crockford characters holding the
+// first 40 bits of an HMAC-SHA256 of the ID.
+type Signer struct {
+	key []byte
+}
+
+// NewSigner returns a Signer using key.
+func NewSigner(key []byte) *Signer {
+	return &Signer{key: append([]byte(nil), key...)}
+}
+
+// Sign returns the 16-character signed form of id.
+func (s *Signer) Sign(id ID) string {
+	return id.String() + s.tag(id).String()
+}
+
+// Verify decodes a string produced by Sign, returning ErrSignature if the
+// signature does not match the ID under the signer's key.
+func (s *Signer) Verify(signed string) (ID, error) {
+	if len(signed) != signedSize {
+		return 0, fmt.Errorf("miniulid: signed form must be %d characters", signedSize)
+	}
+	id, err := Parse(signed[:totalSize])
+	if err != nil {
+		return 0, err
+	}
+	got, err := decode(signed[totalSize:])
+	if err != nil {
+		return 0, err
+	}
+
+	var a, b [binarySize]byte
+	got.putBytes(a[:])
+	s.tag(id).putBytes(b[:])
+	if !hmac.Equal(a[:], b[:]) {
+		return 0, ErrSignature
+	}
+	return id, nil
+}
+
+// tag returns the first 40 bits of the HMAC of id.
+func (s *Signer) tag(id ID) ID {
+	var msg [binarySize]byte
+	id.putBytes(msg[:])
+
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write(msg[:])
+	return idFromBytes(mac.Sum(nil))
+}
@@ -0,0 +1,39 @@
+package miniulid
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSigner(t *testing.T) {
+	s := NewSigner([]byte("secret"))
+	id := ID(56755782866)
+
+	signed := s.Sign(id)
+	if len(signed) != 2*EncodedLen || signed[:EncodedLen] != id.String() {
+		t.Fatalf("Sign: got %q", signed)
+	}
+	if got, err := s.Verify(signed); err != nil || got != id {
+		t.Fatalf("Verify: got %v, %v", got, err)
+	}
+
+	if _, err := NewSigner([]byte("other")).Verify(signed); !errors.Is(err, ErrSignature) {
+		t.Fatalf("expected ErrSignature under another key, got %v", err)
+	}
+
+	for i := 0; i < len(signed); i++ {
+		for j := 0; j < len(Alphabet); j++ {
+			if Alphabet[j] == signed[i] {
+				continue
+			}
+			tampered := signed[:i] + string(Alphabet[j]) + signed[i+1:]
+			if _, err := s.Verify(tampered); err == nil {
+				t.Fatalf("tampering %q at %d not detected: %q", Alphabet[j], i, tampered)
+			}
+		}
+	}
+
+	if _, err := s.Verify(id.String()); err == nil {
+		t.Fatalf("expected length error for unsigned ID")
+	}
+}
@@ -0,0 +1,111 @@
+package miniulid
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+)
+
+// SQLType returns the column type suited to storing IDs in the given SQL
+// dialect: the 40-bit integer form where the dialect has a 64-bit integer
+// type, and CHAR(8) holding the encoded form for unknown dialects.
+func SQLType(dialect string) string {
+	switch strings.ToLower(dialect) {
+	case "postgres", "postgresql", "pgx":
+		return "BIGINT"
+	case "mysql", "mariadb":
+		return "BIGINT UNSIGNED"
+	case "sqlite", "sqlite3":
+		return "INTEGER"
+	default:
+		return "CHAR(8)"
+	}
+}
+
+// ScanTypeError is returned by ID.Scan and NullID.Scan for a column value
+// whose type cannot hold an ID.
+type ScanTypeError struct {
+	// Src is the value that was scanned.
+	Src any
+}
+
+func (e *ScanTypeError) Error() string {
+	return fmt.Sprintf("miniulid: cannot scan %T into ID", e.Src)
+}
+
+// Scan implements sql.Scanner for BIGINT, CHAR(8), and BINARY(5) columns: it
+// accepts an int64 holding the 40-bit value, a string or []byte holding the
+// encoded form, and a 5-byte []byte holding the big-endian binary form. Each
+// form fails with ErrInvalidMinute if its minute-of-day field is out of range.
+// Other types, including NULL, fail with a *ScanTypeError; use NullID for
+// nullable columns.
+func (id *ID) Scan(src any) error {
+	parsed, err := scanID(src)
+	if err != nil {
+		return err
+	}
+	*id = parsed
+	return nil
+}
+
+// Value implements driver.Valuer, returning the 40-bit value as an int64 to
+// match the integer column types SQLType recommends. Convert to TextID or
+// BinaryID to write CHAR(8) or BINARY(5) columns.
+func (id ID) Value() (driver.Value, error) {
+	return id.Int64(), nil
+}
+
+// TextID is an ID that is written to the database as its 8-character encoded
+// form, for CHAR(8) columns. Convert with TextID(id) and ID(t), as for
+// NumericID. It scans any form ID.Scan accepts.
+type TextID ID
+
+// Scan implements sql.Scanner like ID.Scan.
+func (t *TextID) Scan(src any) error {
+	return (*ID)(t).Scan(src)
+}
+
+// Value implements driver.Valuer, returning the encoded form as a string.
+func (t TextID) Value() (driver.Value, error) {
+	return ID(t).String(), nil
+}
+
+// BinaryID is an ID that is written to the database as its 5-byte big-endian
+// form, for BINARY(5) columns. Convert with BinaryID(id) and ID(b), as for
+// NumericID. It scans any form ID.Scan accepts.
+type BinaryID ID
+
+// Scan implements sql.Scanner like ID.Scan.
+func (b *BinaryID) Scan(src any) error {
+	return (*ID)(b).Scan(src)
+}
+
+// Value implements driver.Valuer, returning the binary form as a []byte.
+func (b BinaryID) Value() (driver.Value, error) {
+	return ID(b).MarshalBinary()
+}
+
+// scanID converts a database column value holding the integer, encoded, or
+// binary form. Trailing spaces are trimmed from the encoded form, as drivers
+// may pad CHAR columns wider than 8 characters; other whitespace is still
+// rejected.
+func scanID(src any) (ID, error) {
+	switch v := src.(type) {
+	case int64:
+		id, err := FromInt64(v)
+		if err != nil {
+			return 0, err
+		}
+		return checkMinute(id)
+	case string:
+		return Parse(strings.TrimRight(v, " "))
+	case []byte:
+		if len(v) == binarySize {
+			return checkMinute(idFromBytes(v))
+		}
+		return Parse(string(bytes.TrimRight(v, " ")))
+	default:
+		return 0, &ScanTypeError{Src: src}
+	}
+}
@@ -0,0 +1,95 @@
+package miniulid
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+)
+
+func TestSQLType(t *testing.T) {
+	cases := map[string]string{
+		"postgres":   "BIGINT",
+		"PostgreSQL": "BIGINT",
+		"pgx":        "BIGINT",
+		"mysql":      "BIGINT UNSIGNED",
+		"mariadb":    "BIGINT UNSIGNED",
+		"sqlite":     "INTEGER",
+		"sqlite3":    "INTEGER",
+		"oracle":     "CHAR(8)",
+		"":           "CHAR(8)",
+	}
+	for dialect, want := range cases {
+		if got := SQLType(dialect); got != want {
+			t.Fatalf("SQLType(%q): got %q want %q", dialect, got, want)
+		}
+	}
+}
+
+func TestScanIDPadded(t *testing.T) {
+	id := ID(56755782866)
+	for _, src := range []any{id.String() + "  ", []byte(id.String() + "  ")} {
+		got, err := scanID(src)
+		if err != nil || got != id {
+			t.Fatalf("scanID(%q): got %v, %v want %v", src, got, err, id)
+		}
+	}
+	for _, src := range []any{"1MVE H16J", " 1MVEH16J", "1MVEH16J\t", []byte("1MVEH16J\n")} {
+		if _, err := scanID(src); err == nil {
+			t.Fatalf("scanID(%q): expected error", src)
+		}
+	}
+}
+
+func TestIDScanValue(t *testing.T) {
+	var (
+		_ sql.Scanner   = (*ID)(nil)
+		_ driver.Valuer = ID(0)
+	)
+
+	id := ID(56755782866)
+	binary, _ := id.AppendBinary(nil)
+	for _, src := range []any{id.Int64(), id.String(), []byte(id.String()), id.String() + "  ", binary} {
+		var got ID
+		if err := got.Scan(src); err != nil || got != id {
+			t.Fatalf("Scan(%#v): got %v, %v want %v", src, got, err, id)
+		}
+	}
+	if v, err := id.Value(); err != nil || v != id.Int64() {
+		t.Fatalf("Value: got %#v, %v", v, err)
+	}
+
+	if v, err := TextID(id).Value(); err != nil || v != id.String() {
+		t.Fatalf("TextID.Value: got %#v, %v", v, err)
+	}
+	if v, err := BinaryID(id).Value(); err != nil || !bytes.Equal(v.([]byte), binary) {
+		t.Fatalf("BinaryID.Value: got %#v, %v", v, err)
+	}
+	for _, v := range []driver.Valuer{id, TextID(id), BinaryID(id)} {
+		src, _ := v.Value()
+		var text TextID
+		var bin BinaryID
+		if err := text.Scan(src); err != nil || ID(text) != id {
+			t.Fatalf("TextID.Scan(%#v): got %v, %v", src, ID(text), err)
+		}
+		if err := bin.Scan(src); err != nil || ID(bin) != id {
+			t.Fatalf("BinaryID.Scan(%#v): got %v, %v", src, ID(bin), err)
+		}
+	}
+
+	for _, src := range []any{nil, 3.5, true} {
+		var got ID
+		var typeErr *ScanTypeError
+		if err := got.Scan(src); !errors.As(err, &typeErr) || typeErr.Src != src {
+			t.Fatalf("Scan(%#v): expected *ScanTypeError, got %v", src, err)
+		}
+	}
+	var got ID
+	if err := got.Scan([]byte{0xff, 0xff, 0xff, 0xff, 0xff}); !errors.Is(err, ErrInvalidMinute) {
+		t.Fatalf("Scan of invalid binary: expected ErrInvalidMinute, got %v", err)
+	}
+	if err := got.Scan(int64(maxValue)); !errors.Is(err, ErrInvalidMinute) {
+		t.Fatalf("Scan of invalid int64: expected ErrInvalidMinute, got %v", err)
+	}
+}
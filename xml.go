@@ -0,0 +1,40 @@
+package miniulid
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// MarshalXML encodes the ID as the Crockford string inside start.
+func (id ID) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement(id.String(), start)
+}
+
+// UnmarshalXML decodes an element's text, ignoring surrounding whitespace.
+func (id *ID) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var text string
+	if err := d.DecodeElement(&text, &start); err != nil {
+		return err
+	}
+	parsed, err := Parse(strings.TrimSpace(text))
+	if err != nil {
+		return err
+	}
+	*id = parsed
+	return nil
+}
+
+// MarshalXMLAttr encodes the ID as the Crockford string in an attribute.
+func (id ID) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	return xml.Attr{Name: name, Value: id.String()}, nil
+}
+
+// UnmarshalXMLAttr decodes an attribute value, ignoring surrounding whitespace.
+func (id *ID) UnmarshalXMLAttr(attr xml.Attr) error {
+	parsed, err := Parse(strings.TrimSpace(attr.Value))
+	if err != nil {
+		return err
+	}
+	*id = parsed
+	return nil
+}
@@ -0,0 +1,40 @@
+package miniulid
+
+import (
+	"encoding/xml"
+	"testing"
+	"time"
+)
+
+func TestXML(t *testing.T) {
+	id, err := GenerateWithComponents(time.Date(2024, 8, 18, 15, 30, 0, 0, time.UTC), 1234)
+	if err != nil {
+		t.Fatalf("GenerateWithComponents error: %v", err)
+	}
+
+	type record struct {
+		XMLName xml.Name `xml:"record"`
+		Ref     ID       `xml:"ref,attr"`
+		ID      ID       `xml:"id"`
+	}
+	data, err := xml.Marshal(record{Ref: id, ID: id})
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	if want := `<record ref="` + id.String() + `"><id>` + id.String() + `</id></record>`; string(data) != want {
+		t.Fatalf("Marshal: got %s want %s", data, want)
+	}
+
+	pretty := "<record ref=\" " + id.String() + " \">\n  <id>\n    " + id.String() + "\n  </id>\n</record>"
+	var back record
+	if err := xml.Unmarshal([]byte(pretty), &back); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if back.ID != id || back.Ref != id {
+		t.Fatalf("round trip: got %v/%v want %v", back.ID, back.Ref, id)
+	}
+
+	if err := xml.Unmarshal([]byte(`<record><id>nope</id></record>`), &back); err == nil {
+		t.Fatalf("expected error for invalid ID")
+	}
+}